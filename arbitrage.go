@@ -0,0 +1,309 @@
+package krakenapi
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ArbitrageLeg is a trade of a triangle
+type ArbitrageLeg struct {
+	// Canonical pair name
+	Pair string
+	// Asset ids converted from and to
+	From string
+	To   string
+	// True if the leg buys the pair's base with its quote, false if it sells the base
+	Buy bool
+}
+
+// Direction returns the AddOrder direction of the leg
+func (l ArbitrageLeg) Direction() string {
+	if l.Buy {
+		return "buy"
+	}
+	return "sell"
+}
+
+// Triangle is a cycle of three trades starting and ending in the same asset
+type Triangle struct {
+	Legs [3]ArbitrageLeg
+}
+
+func (t Triangle) String() string {
+	assets := []string{t.Legs[0].From, t.Legs[1].From, t.Legs[2].From, t.Legs[2].To}
+	return strings.Join(assets, " -> ")
+}
+
+// ArbitrageOpportunity is a triangle whose net return after fees is above the scanner's threshold
+type ArbitrageOpportunity struct {
+	Triangle Triangle
+	// Prices taken by each leg
+	Prices [3]float64
+	// Net return of the cycle after taker fees, e.g. 0.001 for 0.1%
+	Return float64
+}
+
+// ArbitrageScanner finds three-legged price inconsistencies between Kraken pairs.
+// Every leg is assumed to take liquidity at the best price and to pay the taker fee in the quote currency,
+// which is Kraken's default, and leg volumes are rounded down to the pairs' lot decimals.
+type ArbitrageScanner struct {
+	// Minimum net return of an opportunity, e.g. 0.001 for 0.1%
+	Threshold float64
+	// Taker fee in percent per pair, overriding the lowest tier of AssetPairInfo.Fees
+	Fees map[string]float64
+	// Amount of each start asset Scan cycles through a triangle, one unit if the asset has none
+	Amounts map[string]float64
+
+	pairs     map[string]AssetPairInfo
+	triangles []Triangle
+}
+
+// NewArbitrageScanner creates an ArbitrageScanner over the triangles of the given asset pairs
+func NewArbitrageScanner(pairs AssetPairsResponse, threshold float64) *ArbitrageScanner {
+	s := &ArbitrageScanner{
+		Threshold: threshold,
+		pairs:     make(map[string]AssetPairInfo),
+	}
+
+	edges := make(map[string][]ArbitrageLeg)
+	names := pairs.GetPairs()
+	sort.Strings(names)
+	for _, name := range names {
+		info := pairs.GetAssetPair(name)
+		if info.Base == "" || info.Quote == "" {
+			continue
+		}
+		s.pairs[name] = info
+		edges[info.Base] = append(edges[info.Base], ArbitrageLeg{Pair: name, From: info.Base, To: info.Quote})
+		edges[info.Quote] = append(edges[info.Quote], ArbitrageLeg{Pair: name, From: info.Quote, To: info.Base, Buy: true})
+	}
+
+	assets := make([]string, 0, len(edges))
+	for asset := range edges {
+		assets = append(assets, asset)
+	}
+	sort.Strings(assets)
+
+	// Each cycle is listed once per direction, starting from its smallest asset
+	for _, start := range assets {
+		for _, first := range edges[start] {
+			if first.To <= start {
+				continue
+			}
+			for _, second := range edges[first.To] {
+				if second.To <= start || second.Pair == first.Pair {
+					continue
+				}
+				for _, third := range edges[second.To] {
+					if third.To == start && third.Pair != second.Pair {
+						s.triangles = append(s.triangles, Triangle{Legs: [3]ArbitrageLeg{first, second, third}})
+					}
+				}
+			}
+		}
+	}
+
+	return s
+}
+
+// Triangles returns every triangle of the scanner's pairs
+func (s *ArbitrageScanner) Triangles() []Triangle {
+	return s.triangles
+}
+
+// takerFee returns the taker fee of pair as a fraction
+func (s *ArbitrageScanner) takerFee(pair string) float64 {
+	if fee, ok := s.Fees[pair]; ok {
+		return fee / 100
+	}
+	if fees := s.pairs[pair].Fees; len(fees) > 0 && len(fees[0]) > 1 {
+		return fees[0][1] / 100
+	}
+	return 0
+}
+
+// Scan returns the opportunities above the threshold at the prices of snapshot, best first
+func (s *ArbitrageScanner) Scan(snapshot *MarketSnapshot) []ArbitrageOpportunity {
+	var opportunities []ArbitrageOpportunity
+	for _, triangle := range s.triangles {
+		var prices [3]float64
+		ok := true
+		for i, leg := range triangle.Legs {
+			ticker, found := snapshot.Tickers[leg.Pair]
+			if !found {
+				ok = false
+				break
+			}
+			if leg.Buy {
+				prices[i] = ticker.Ask.Price.Float64()
+			} else {
+				prices[i] = ticker.Bid.Price.Float64()
+			}
+			if prices[i] <= 0 {
+				ok = false
+				break
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		amount, found := s.Amounts[triangle.Legs[0].From]
+		if !found {
+			amount = 1
+		}
+		ret, ok := s.cycleReturn(triangle, prices, amount)
+		if ok && ret > s.Threshold {
+			opportunities = append(opportunities, ArbitrageOpportunity{Triangle: triangle, Prices: prices, Return: ret})
+		}
+	}
+
+	sort.Slice(opportunities, func(i, j int) bool {
+		return opportunities[i].Return > opportunities[j].Return
+	})
+	return opportunities
+}
+
+// cycleReturn returns the net return of amount cycled through the triangle at given prices,
+// false if a leg's volume rounds to zero
+func (s *ArbitrageScanner) cycleReturn(triangle Triangle, prices [3]float64, amount float64) (float64, bool) {
+	current := amount
+	for i, leg := range triangle.Legs {
+		var volume float64
+		if leg.Buy {
+			volume = s.buyVolume(leg, current, prices[i])
+		} else {
+			volume = truncateDecimals(current, s.pairs[leg.Pair].LotDecimals)
+		}
+		if volume <= 0 {
+			return 0, false
+		}
+		current = s.legResult(leg, volume, prices[i])
+	}
+	return current/amount - 1, true
+}
+
+// buyVolume returns the base volume a buy leg can afford with quote at price, keeping the taker fee
+// charged in quote, rounded down to the pair's lot decimals
+func (s *ArbitrageScanner) buyVolume(leg ArbitrageLeg, quote, price float64) float64 {
+	return truncateDecimals(quote*(1-s.takerFee(leg.Pair))/price, s.pairs[leg.Pair].LotDecimals)
+}
+
+// legResult returns the amount of the leg's To asset received for volume at price.
+// The fee of a buy was already set aside from the quote, the fee of a sell is taken from its proceeds.
+func (s *ArbitrageScanner) legResult(leg ArbitrageLeg, volume, price float64) float64 {
+	if leg.Buy {
+		return volume
+	}
+	return volume * price * (1 - s.takerFee(leg.Pair))
+}
+
+// ArbitrageSize is a depth-aware execution plan of a triangle
+type ArbitrageSize struct {
+	// Amount of the start asset put into the cycle
+	Amount float64
+	// Amount of the start asset received at the end of the cycle
+	Result float64
+	// Net return after fees and lot rounding
+	Return float64
+	// Base volume of each leg, rounded down to the pair's lot decimals
+	Volumes [3]float64
+	// Average fill price of each leg
+	Prices [3]float64
+}
+
+// Size finds the largest amount of the start asset, up to maxAmount, that can be cycled through
+// the triangle against the given order books while keeping the net return above the threshold.
+// Books are keyed by canonical pair name. Leg volumes are rounded down to each pair's lot decimals.
+func (s *ArbitrageScanner) Size(triangle Triangle, books map[string]*OrderBook, maxAmount float64) (*ArbitrageSize, error) {
+	for _, leg := range triangle.Legs {
+		if _, ok := books[leg.Pair]; !ok {
+			return nil, fmt.Errorf("no order book for %s", leg.Pair)
+		}
+	}
+
+	// The return only gets worse with size, so bisect for the largest amount above the threshold
+	if size, err := s.simulate(triangle, books, maxAmount); err == nil && size.Return > s.Threshold {
+		return size, nil
+	}
+	var best *ArbitrageSize
+	low, high := 0.0, maxAmount
+	for i := 0; i < 50; i++ {
+		amount := (low + high) / 2
+		size, err := s.simulate(triangle, books, amount)
+		if err == nil && size.Return > s.Threshold {
+			best, low = size, amount
+		} else {
+			high = amount
+		}
+	}
+
+	if best == nil {
+		return nil, errors.New("no size keeps the return above the threshold")
+	}
+	return best, nil
+}
+
+// simulate cycles amount of the start asset through the triangle, taking liquidity from books
+func (s *ArbitrageScanner) simulate(triangle Triangle, books map[string]*OrderBook, amount float64) (*ArbitrageSize, error) {
+	size := &ArbitrageSize{Amount: amount}
+	current := amount
+	for i, leg := range triangle.Legs {
+		book := books[leg.Pair]
+		info := s.pairs[leg.Pair]
+
+		var volume, price float64
+		var err error
+		if leg.Buy {
+			// Keep the fee charged in quote out of what the order spends
+			if volume, err = spendQuote(book, current*(1-s.takerFee(leg.Pair))); err != nil {
+				return nil, err
+			}
+		} else {
+			volume = current
+		}
+
+		volume = truncateDecimals(volume, info.LotDecimals)
+		if volume <= 0 {
+			return nil, errors.New("volume rounds to zero")
+		}
+		side := BidSide
+		if leg.Buy {
+			side = AskSide
+		}
+		if price, err = book.FillPrice(side, volume); err != nil {
+			return nil, err
+		}
+
+		size.Volumes[i], size.Prices[i] = volume, price
+		current = s.legResult(leg, volume, price)
+	}
+
+	size.Result = current
+	size.Return = current/amount - 1
+	return size, nil
+}
+
+// spendQuote returns the base volume bought by spending quote against the asks of book
+func spendQuote(book *OrderBook, quote float64) (float64, error) {
+	var volume float64
+	for _, level := range book.Asks {
+		cost := level.Price * level.Amount
+		if cost >= quote {
+			return volume + quote/level.Price, nil
+		}
+		volume += level.Amount
+		quote -= cost
+	}
+	if volume == 0 {
+		return 0, ErrEmptyBook
+	}
+	return 0, fmt.Errorf("asks only hold %v", volume)
+}
+
+// truncateDecimals rounds value down to given decimals
+func truncateDecimals(value float64, decimals int) float64 {
+	return roundDecimals(value, decimals, RoundFloor)
+}
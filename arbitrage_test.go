@@ -0,0 +1,80 @@
+package krakenapi
+
+import (
+	"testing"
+)
+
+func TestArbitrageScanner(t *testing.T) {
+	pairs := &AssetPairs{
+		"XXBTZEUR": {Base: "XXBT", Quote: "ZEUR", LotDecimals: 8, Fees: [][]float64{{0, 0.26}}},
+		"XETHXXBT": {Base: "XETH", Quote: "XXBT", LotDecimals: 8, Fees: [][]float64{{0, 0.26}}},
+		"XETHZEUR": {Base: "XETH", Quote: "ZEUR", LotDecimals: 8, Fees: [][]float64{{0, 0.26}}},
+		"XXRPZJPY": {Base: "XXRP", Quote: "ZJPY", LotDecimals: 8},
+	}
+	scanner := NewArbitrageScanner(pairs, 0.001)
+
+	if triangles := scanner.Triangles(); len(triangles) != 2 {
+		t.Fatalf("Triangles() should list the triangle in both directions, got %v", triangles)
+	}
+
+	ticker := func(bid, ask float64) *TickerValues {
+		return &TickerValues{Bid: TickerLevel{Price: DecimalFromFloat(bid)}, Ask: TickerLevel{Price: DecimalFromFloat(ask)}}
+	}
+	// EUR -> XBT at 20000, XBT -> ETH at 0.05, ETH -> EUR at 1030 returns 3% before fees
+	snapshot := &MarketSnapshot{Tickers: map[string]*TickerValues{
+		"XXBTZEUR": ticker(19990, 20000),
+		"XETHXXBT": ticker(0.0499, 0.05),
+		"XETHZEUR": ticker(1030, 1031),
+	}}
+
+	opportunities := scanner.Scan(snapshot)
+	if len(opportunities) != 1 {
+		t.Fatalf("Scan() should find one opportunity, got %+v", opportunities)
+	}
+	opportunity := opportunities[0]
+	// One XETH is sold for EUR, buys pay the fee in quote and volumes are rounded to 8 decimals
+	xbt := truncateDecimals(1030*(1-0.0026)*(1-0.0026)/20000, 8)
+	expected := truncateDecimals(xbt*(1-0.0026)/0.05, 8) - 1
+	if !almostEqual(opportunity.Return, expected) {
+		t.Errorf("Scan() should return the net return after fees and lot rounding %v, got %v", expected, opportunity.Return)
+	}
+
+	if opportunity.Triangle.String() != "XETH -> ZEUR -> XXBT -> XETH" && opportunity.Triangle.String() != "ZEUR -> XXBT -> XETH -> ZEUR" {
+		t.Errorf("Scan() should return the profitable direction, got %s", opportunity.Triangle)
+	}
+
+	coarse := &AssetPairs{}
+	for name, info := range *pairs {
+		(*coarse)[name] = info
+	}
+	info := (*coarse)["XETHXXBT"]
+	info.LotDecimals = 4
+	(*coarse)["XETHXXBT"] = info
+	small := NewArbitrageScanner(coarse, 0.001)
+	small.Amounts = map[string]float64{"XETH": 0.0001}
+	if opportunities := small.Scan(snapshot); len(opportunities) != 0 {
+		t.Errorf("Scan() should drop opportunities eaten by lot rounding, got %+v", opportunities)
+	}
+
+	// Rotate the triangle to start in EUR and size it against thin books
+	triangle := opportunity.Triangle
+	for triangle.Legs[0].From != "ZEUR" {
+		triangle.Legs[0], triangle.Legs[1], triangle.Legs[2] = triangle.Legs[1], triangle.Legs[2], triangle.Legs[0]
+	}
+	books := map[string]*OrderBook{
+		"XXBTZEUR": {Asks: []OrderBookItem{{Price: 20000, Amount: 1}, {Price: 21000, Amount: 10}}},
+		"XETHXXBT": {Asks: []OrderBookItem{{Price: 0.05, Amount: 100}}},
+		"XETHZEUR": {Bids: []OrderBookItem{{Price: 1030, Amount: 100}}},
+	}
+
+	size, err := scanner.Size(triangle, books, 100000)
+	if err != nil {
+		t.Fatalf("Size() should not return an error, got %s", err)
+	}
+	if size.Amount <= 20000 || size.Amount >= 100000 || size.Return <= scanner.Threshold || size.Return > scanner.Threshold+1e-4 {
+		t.Errorf("Size() should stop where the second XBT level eats the return, got %+v", size)
+	}
+	if size.Volumes[0] != truncateDecimals(size.Volumes[0], 8) {
+		t.Errorf("Size() should round volumes to lot decimals, got %v", size.Volumes)
+	}
+}
@@ -0,0 +1,127 @@
+package krakenapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AssetSuffix is the suffix Kraken appends to asset codes of balances held in a special way
+type AssetSuffix string
+
+// AssetSuffix values
+const (
+	SuffixNone         AssetSuffix = ""
+	SuffixStaked       AssetSuffix = ".S"
+	SuffixOptInRewards AssetSuffix = ".M"
+	SuffixHold         AssetSuffix = ".HOLD"
+	SuffixFutures      AssetSuffix = ".F"
+)
+
+// AssetCode is a parsed asset code as used by Balance and ledger entries
+type AssetCode struct {
+	// Code as returned by Kraken, e.g. XXBT or XBT.M
+	Code string
+	// Kraken asset id of the base asset, e.g. XXBT
+	Asset string
+	// Common ticker of the base asset, e.g. BTC
+	Ticker string
+	// Suffix of the code, e.g. SuffixOptInRewards
+	Suffix AssetSuffix
+}
+
+func (c AssetCode) String() string {
+	return c.Code
+}
+
+// AssetNormalizer parses asset codes and maps them to common tickers and back, using the
+// assets of an Assets response. Lookups are case insensitive.
+type AssetNormalizer struct {
+	assets map[string]AssetInfo
+	ids    map[string]string
+}
+
+// NewAssetNormalizer creates an AssetNormalizer for the assets of an Assets response
+func NewAssetNormalizer(assets AssetsResponse) *AssetNormalizer {
+	n := &AssetNormalizer{
+		assets: make(map[string]AssetInfo),
+		ids:    make(map[string]string),
+	}
+
+	// Ids win over altnames, altnames over common tickers
+	for _, id := range assets.GetAssets() {
+		n.assets[id] = assets.GetAsset(id)
+		n.ids[strings.ToUpper(id)] = id
+	}
+	for id, info := range n.assets {
+		n.register(info.Altname, id)
+	}
+	for id, info := range n.assets {
+		if alias, ok := assetAliases[info.Altname]; ok {
+			n.register(alias, id)
+		}
+	}
+
+	return n
+}
+
+// LoadAssetNormalizer creates an AssetNormalizer for all assets of api
+func LoadAssetNormalizer(api PublicAPI) (*AssetNormalizer, error) {
+	assets, err := api.Assets()
+	if err != nil {
+		return nil, err
+	}
+	return NewAssetNormalizer(assets), nil
+}
+
+func (n *AssetNormalizer) register(name, id string) {
+	key := strings.ToUpper(name)
+	if _, ok := n.ids[key]; !ok && name != "" {
+		n.ids[key] = id
+	}
+}
+
+// Parse splits code into its base asset and suffix. The base asset may be given by its id,
+// altname or common ticker. Unknown base assets are kept as they are.
+func (n *AssetNormalizer) Parse(code string) AssetCode {
+	base, suffix := code, SuffixNone
+	if i := strings.IndexByte(code, '.'); i >= 0 {
+		base, suffix = code[:i], AssetSuffix(strings.ToUpper(code[i:]))
+	}
+
+	parsed := AssetCode{Code: code, Asset: base, Ticker: base, Suffix: suffix}
+	if id, ok := n.ids[strings.ToUpper(base)]; ok {
+		parsed.Asset = id
+		parsed.Ticker = n.ticker(id)
+	}
+	return parsed
+}
+
+// Ticker returns the common ticker of code, e.g. BTC for XXBT and XBT.M
+func (n *AssetNormalizer) Ticker(code string) string {
+	return n.Parse(code).Ticker
+}
+
+// Code returns the Kraken code of asset, given by id, altname or common ticker, with suffix.
+// Codes without suffix are asset ids (XXBT), codes with suffix use the altname (XBT.M).
+func (n *AssetNormalizer) Code(asset string, suffix AssetSuffix) (string, error) {
+	id, ok := n.ids[strings.ToUpper(asset)]
+	if !ok {
+		return "", fmt.Errorf("unknown asset %s", asset)
+	}
+	if suffix == SuffixNone {
+		return id, nil
+	}
+	return n.assets[id].Altname + string(suffix), nil
+}
+
+// ticker returns the common ticker of the asset with given id
+func (n *AssetNormalizer) ticker(id string) string {
+	altname := n.assets[id].Altname
+	if altname == "" {
+		altname = id
+	}
+	if alias, ok := assetAliases[altname]; ok {
+		return alias
+	}
+	return altname
+}
@@ -0,0 +1,55 @@
+package krakenapi
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestAssetNormalizer(t *testing.T) {
+	api := newTestAPI(func(method string, values url.Values) string {
+		return `{"error":[],"result":{` +
+			`"XXBT":{"altname":"XBT"},"ZUSD":{"altname":"USD"},"XETH":{"altname":"ETH"},` +
+			`"ETH2":{"altname":"ETH2"},"DOT":{"altname":"DOT"},"XXDG":{"altname":"XDG"},` +
+			`"XBT.M":{"altname":"XBT.M"},"ETH2.S":{"altname":"ETH2.S"}}}`
+	})
+
+	n, err := LoadAssetNormalizer(api.Public())
+	if err != nil {
+		t.Fatalf("LoadAssetNormalizer() should not return an error, got %s", err)
+	}
+
+	tests := []struct {
+		code   string
+		asset  string
+		ticker string
+		suffix AssetSuffix
+	}{
+		{"XXBT", "XXBT", "BTC", SuffixNone},
+		{"ZUSD", "ZUSD", "USD", SuffixNone},
+		{"XBT.M", "XXBT", "BTC", SuffixOptInRewards},
+		{"ETH2.S", "ETH2", "ETH2", SuffixStaked},
+		{"DOT.S", "DOT", "DOT", SuffixStaked},
+		{"USD.HOLD", "ZUSD", "USD", SuffixHold},
+		{"XXDG", "XXDG", "DOGE", SuffixNone},
+		{"FOO.F", "FOO", "FOO", SuffixFutures},
+	}
+	for _, test := range tests {
+		parsed := n.Parse(test.code)
+		if parsed.Asset != test.asset || parsed.Ticker != test.ticker || parsed.Suffix != test.suffix {
+			t.Errorf("Parse(%s) should return %s %s %q, got %+v", test.code, test.asset, test.ticker, test.suffix, parsed)
+		}
+	}
+
+	if code, _ := n.Code("BTC", SuffixNone); code != "XXBT" {
+		t.Errorf("Code(BTC) should return XXBT, got %s", code)
+	}
+	if code, _ := n.Code("btc", SuffixOptInRewards); code != "XBT.M" {
+		t.Errorf("Code(btc, .M) should return XBT.M, got %s", code)
+	}
+	if code, _ := n.Code("USD", SuffixHold); code != "USD.HOLD" {
+		t.Errorf("Code(USD, .HOLD) should return USD.HOLD, got %s", code)
+	}
+	if _, err := n.Code("FOO", SuffixNone); err == nil {
+		t.Errorf("Code(FOO) should fail")
+	}
+}
@@ -0,0 +1,242 @@
+package krakenapi
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// BarKind selects when a CandleBuilder closes a bar
+type BarKind int
+
+// BarKind values
+const (
+	// Bars of a fixed time duration
+	TimeBars BarKind = iota
+	// Bars holding a fixed base volume
+	VolumeBars
+	// Bars holding a fixed quote notional (price * volume)
+	DollarBars
+	// Bars holding a fixed number of trades
+	TickBars
+)
+
+// CandleBuilder builds candles from trades, as returned by Trades or a websocket feed.
+// Time bars are aligned to the unix epoch and may have any duration of whole seconds. Bars
+// of the other kinds close with the trade that makes them reach their threshold, and start
+// at the time of their first trade.
+type CandleBuilder struct {
+	// Emit flat bars at the last close for time intervals without trades (time bars only)
+	FillGaps bool
+	// How long a time bar stays open for late trades after it ended, measured by trade time
+	Lateness time.Duration
+
+	kind      BarKind
+	seconds   int64
+	threshold float64
+
+	// time bars
+	open      map[int64]*candle
+	next      int64
+	started   bool
+	watermark int64
+	lastClose float64
+	late      int
+
+	// threshold bars
+	current *candle
+	measure float64
+}
+
+// candle is a bar under construction
+type candle struct {
+	ohlc  *OHLC
+	cost  float64
+	first int64
+	last  int64
+}
+
+// NewTimeBarBuilder creates a CandleBuilder emitting bars of duration d, which is rounded down to whole seconds
+func NewTimeBarBuilder(d time.Duration) *CandleBuilder {
+	seconds := int64(d / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	return &CandleBuilder{
+		kind:    TimeBars,
+		seconds: seconds,
+		open:    make(map[int64]*candle),
+	}
+}
+
+// NewVolumeBarBuilder creates a CandleBuilder emitting a bar each time volume base currency is traded
+func NewVolumeBarBuilder(volume float64) *CandleBuilder {
+	return &CandleBuilder{kind: VolumeBars, threshold: volume}
+}
+
+// NewDollarBarBuilder creates a CandleBuilder emitting a bar each time notional quote currency is traded
+func NewDollarBarBuilder(notional float64) *CandleBuilder {
+	return &CandleBuilder{kind: DollarBars, threshold: notional}
+}
+
+// NewTickBarBuilder creates a CandleBuilder emitting a bar every count trades
+func NewTickBarBuilder(count int) *CandleBuilder {
+	return &CandleBuilder{kind: TickBars, threshold: float64(count)}
+}
+
+// Add adds a trade and returns the bars it completed, oldest first
+func (b *CandleBuilder) Add(trade TradeInfo) []*OHLC {
+	if b.kind == TimeBars {
+		return b.addTimed(trade)
+	}
+	return b.addThreshold(trade)
+}
+
+// Flush returns the bars that are still open, oldest first, and resets them
+func (b *CandleBuilder) Flush() []*OHLC {
+	if b.kind != TimeBars {
+		if b.current == nil {
+			return nil
+		}
+		bar := b.current.finish()
+		b.current, b.measure = nil, 0
+		return []*OHLC{bar}
+	}
+
+	var bars []*OHLC
+	for len(b.open) > 0 {
+		bars = append(bars, b.emitNext()...)
+	}
+	return bars
+}
+
+// LateTrades returns the number of trades dropped because their time bar was already emitted
+func (b *CandleBuilder) LateTrades() int {
+	return b.late
+}
+
+func (b *CandleBuilder) addThreshold(trade TradeInfo) []*OHLC {
+	if b.current == nil {
+		b.current = newCandle(time.Unix(trade.Time, 0), trade)
+	}
+	b.current.add(trade)
+
+	switch b.kind {
+	case VolumeBars:
+		b.measure += trade.VolumeFloat
+	case DollarBars:
+		b.measure += trade.VolumeFloat * trade.PriceFloat
+	case TickBars:
+		b.measure++
+	}
+
+	if b.measure < b.threshold {
+		return nil
+	}
+	bar := b.current.finish()
+	b.current, b.measure = nil, 0
+	return []*OHLC{bar}
+}
+
+func (b *CandleBuilder) addTimed(trade TradeInfo) []*OHLC {
+	start := trade.Time - mod(trade.Time, b.seconds)
+	if !b.started {
+		b.next, b.started = start, true
+	}
+	if start < b.next {
+		b.late++
+		return nil
+	}
+
+	bar, ok := b.open[start]
+	if !ok {
+		bar = newCandle(time.Unix(start, 0), trade)
+		b.open[start] = bar
+	}
+	bar.add(trade)
+	if trade.Time > b.watermark {
+		b.watermark = trade.Time
+	}
+
+	// Emit the bars that ended more than Lateness before the newest trade
+	closed := b.watermark - int64(b.Lateness/time.Second)
+	var bars []*OHLC
+	for len(b.open) > 0 && b.next+b.seconds <= closed {
+		bars = append(bars, b.emitNext()...)
+	}
+	return bars
+}
+
+// emitNext emits the next time bar, or the flat bars up to the next open bar if there are gaps
+func (b *CandleBuilder) emitNext() []*OHLC {
+	if bar, ok := b.open[b.next]; ok {
+		delete(b.open, b.next)
+		b.next += b.seconds
+		ohlc := bar.finish()
+		b.lastClose = ohlc.Close
+		return []*OHLC{ohlc}
+	}
+
+	// The next bar had no trades, skip to the oldest open one
+	starts := make([]int64, 0, len(b.open))
+	for start := range b.open {
+		starts = append(starts, start)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i] < starts[j] })
+
+	var bars []*OHLC
+	for ; b.next < starts[0]; b.next += b.seconds {
+		if b.FillGaps {
+			bars = append(bars, flatCandle(time.Unix(b.next, 0), b.lastClose))
+		}
+	}
+	return bars
+}
+
+func newCandle(start time.Time, trade TradeInfo) *candle {
+	return &candle{
+		ohlc: &OHLC{
+			Time: start,
+			Open: trade.PriceFloat,
+			High: trade.PriceFloat,
+			Low:  trade.PriceFloat,
+		},
+		first: trade.Time,
+		last:  trade.Time,
+	}
+}
+
+func flatCandle(start time.Time, price float64) *OHLC {
+	return &OHLC{Time: start, Open: price, High: price, Low: price, Close: price, Vwap: price}
+}
+
+// add adds a trade, late trades within the bar only move open and close if they are older or newer
+func (c *candle) add(trade TradeInfo) {
+	if trade.Time < c.first {
+		c.ohlc.Open, c.first = trade.PriceFloat, trade.Time
+	}
+	if trade.Time >= c.last {
+		c.ohlc.Close, c.last = trade.PriceFloat, trade.Time
+	}
+	c.ohlc.High = math.Max(c.ohlc.High, trade.PriceFloat)
+	c.ohlc.Low = math.Min(c.ohlc.Low, trade.PriceFloat)
+	c.ohlc.Volume += trade.VolumeFloat
+	c.ohlc.Count++
+	c.cost += trade.PriceFloat * trade.VolumeFloat
+}
+
+func (c *candle) finish() *OHLC {
+	if c.ohlc.Volume > 0 {
+		c.ohlc.Vwap = c.cost / c.ohlc.Volume
+	}
+	return c.ohlc
+}
+
+// mod returns the non negative remainder of a / b
+func mod(a, b int64) int64 {
+	m := a % b
+	if m < 0 {
+		m += b
+	}
+	return m
+}
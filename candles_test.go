@@ -0,0 +1,84 @@
+package krakenapi
+
+import (
+	"testing"
+	"time"
+)
+
+func trade(ts int64, price, volume float64) TradeInfo {
+	return TradeInfo{Time: ts, PriceFloat: price, VolumeFloat: volume}
+}
+
+func TestTimeBars(t *testing.T) {
+	b := NewTimeBarBuilder(3 * time.Minute)
+	b.FillGaps = true
+	b.Lateness = 30 * time.Second
+
+	var bars []*OHLC
+	for _, tr := range []TradeInfo{
+		trade(1688601600, 10, 1),
+		trade(1688601660, 12, 1),
+		trade(1688601700, 9, 2),
+		trade(1688601790, 11, 1), // second bar
+		trade(1688601750, 13, 1), // late but within the first bar's lateness
+		trade(1688602200, 14, 1), // fourth bar, closes the first and second ones
+		trade(1688601700, 15, 1), // too late, first bar was emitted
+	} {
+		bars = append(bars, b.Add(tr)...)
+	}
+	bars = append(bars, b.Flush()...)
+
+	if len(bars) != 4 || b.LateTrades() != 1 {
+		t.Fatalf("TimeBars should emit 3 bars, 1 flat bar and drop 1 late trade, got %d bars and %d late trades", len(bars), b.LateTrades())
+	}
+
+	first := bars[0]
+	if first.Time.Unix() != 1688601600 || first.Open != 10 || first.High != 13 || first.Low != 9 || first.Close != 13 || first.Count != 4 {
+		t.Errorf("First bar should include the late trade, got %+v", first)
+	}
+	if first.Volume != 5 || first.Vwap != (10+12+18+13)/5.0 {
+		t.Errorf("First bar should have volume 5 and its VWAP, got %+v", first)
+	}
+	if flat := bars[2]; flat.Time.Unix() != 1688601960 || flat.Close != 11 || flat.Count != 0 {
+		t.Errorf("Gap should be filled with a flat bar at the last close, got %+v", flat)
+	}
+	if last := bars[3]; last.Time.Unix() != 1688602140 || last.Close != 14 {
+		t.Errorf("Flush should emit the open bar, got %+v", last)
+	}
+}
+
+func TestThresholdBars(t *testing.T) {
+	trades := []TradeInfo{trade(1, 10, 1), trade(2, 20, 2), trade(3, 10, 1), trade(4, 30, 1), trade(5, 10, 5)}
+
+	tests := []struct {
+		name    string
+		builder *CandleBuilder
+		counts  []int
+	}{
+		{"volume", NewVolumeBarBuilder(3), []int{2, 3}},
+		{"dollar", NewDollarBarBuilder(40), []int{2, 2, 1}},
+		{"tick", NewTickBarBuilder(2), []int{2, 2, 1}},
+	}
+	for _, test := range tests {
+		var bars []*OHLC
+		for _, tr := range trades {
+			bars = append(bars, test.builder.Add(tr)...)
+		}
+		bars = append(bars, test.builder.Flush()...)
+
+		var counts []int
+		for _, bar := range bars {
+			counts = append(counts, bar.Count)
+		}
+		if len(counts) != len(test.counts) {
+			t.Errorf("%s bars should have trade counts %v, got %v", test.name, test.counts, counts)
+			continue
+		}
+		for i := range counts {
+			if counts[i] != test.counts[i] {
+				t.Errorf("%s bars should have trade counts %v, got %v", test.name, test.counts, counts)
+				break
+			}
+		}
+	}
+}
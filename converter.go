@@ -0,0 +1,221 @@
+package krakenapi
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PriceSource selects the ticker price used for conversions
+type PriceSource int
+
+// PriceSource values
+const (
+	PriceMid PriceSource = iota
+	PriceBid
+	PriceAsk
+)
+
+// DefaultHubs are the assets multi-hop conversions may pass through
+var DefaultHubs = []string{"XXBT", "ZUSD", "ZEUR"}
+
+// ConversionLeg is a step of a conversion path
+type ConversionLeg struct {
+	// Canonical pair name
+	Pair string
+	// Asset ids converted from and to
+	From string
+	To   string
+	// Ticker price of the pair used
+	Price float64
+	// True if the leg converts the pair's quote into its base, dividing by the price
+	Inverse bool
+}
+
+// Conversion is the result of a Converter conversion
+type Conversion struct {
+	From   string
+	To     string
+	Amount float64
+	Result float64
+	Path   []ConversionLeg
+	// Age of the prices used
+	Age time.Duration
+}
+
+// Rate returns the overall conversion rate
+func (c *Conversion) Rate() float64 {
+	if c.Amount == 0 {
+		return 0
+	}
+	return c.Result / c.Amount
+}
+
+// converterEdge is a pair seen from one of its assets
+type converterEdge struct {
+	pair    string
+	to      string
+	ticker  *TickerValues
+	inverse bool
+}
+
+// Converter converts amounts between assets over the graph of asset pairs, using the
+// prices of a MarketSnapshot. Assets may be given by id (XXBT), altname (XBT) or common ticker (BTC).
+type Converter struct {
+	// Assets multi-hop conversions may pass through, DefaultHubs if empty
+	Hubs []string
+	// Maximum number of legs of a conversion path
+	MaxLegs int
+
+	snapshot *MarketSnapshot
+	edges    map[string][]converterEdge
+	names    map[string]string
+	now      func() time.Time
+}
+
+// NewConverter creates a Converter for the asset pairs with a ticker in snapshot
+func NewConverter(pairs AssetPairsResponse, snapshot *MarketSnapshot) *Converter {
+	c := &Converter{
+		Hubs:     DefaultHubs,
+		MaxLegs:  3,
+		snapshot: snapshot,
+		edges:    make(map[string][]converterEdge),
+		names:    make(map[string]string),
+		now:      time.Now,
+	}
+
+	for _, name := range pairs.GetPairs() {
+		info := pairs.GetAssetPair(name)
+		ticker, ok := snapshot.Tickers[name]
+		if !ok || info.Base == "" || info.Quote == "" {
+			continue
+		}
+		c.edges[info.Base] = append(c.edges[info.Base], converterEdge{pair: name, to: info.Quote, ticker: ticker})
+		c.edges[info.Quote] = append(c.edges[info.Quote], converterEdge{pair: name, to: info.Base, ticker: ticker, inverse: true})
+
+		c.addName(info.Base, info.Base)
+		c.addName(info.Quote, info.Quote)
+		if parts := strings.Split(info.WSName, "/"); len(parts) == 2 {
+			c.addName(parts[0], info.Base)
+			c.addName(parts[1], info.Quote)
+			if alias, ok := assetAliases[parts[0]]; ok {
+				c.addName(alias, info.Base)
+			}
+			if alias, ok := assetAliases[parts[1]]; ok {
+				c.addName(alias, info.Quote)
+			}
+		}
+	}
+
+	return c
+}
+
+func (c *Converter) addName(name, id string) {
+	key := strings.ToUpper(name)
+	if _, ok := c.names[key]; !ok {
+		c.names[key] = id
+	}
+}
+
+func (c *Converter) asset(name string) (string, error) {
+	if id, ok := c.names[strings.ToUpper(name)]; ok {
+		return id, nil
+	}
+	return "", fmt.Errorf("no pair trades %s", name)
+}
+
+// Convert converts amount of from into to. It uses the path with the fewest legs, direct or
+// inverse pairs first and then multi-hop paths through the hubs, picking the best result among
+// paths of the same length.
+func (c *Converter) Convert(amount float64, from, to string, source PriceSource) (*Conversion, error) {
+	fromID, err := c.asset(from)
+	if err != nil {
+		return nil, err
+	}
+	toID, err := c.asset(to)
+	if err != nil {
+		return nil, err
+	}
+
+	conversion := &Conversion{From: fromID, To: toID, Amount: amount, Result: amount, Age: c.now().Sub(c.snapshot.Time)}
+	if fromID == toID {
+		return conversion, nil
+	}
+
+	hubs := make(map[string]bool)
+	hubList := c.Hubs
+	if len(hubList) == 0 {
+		hubList = DefaultHubs
+	}
+	for _, hub := range hubList {
+		if id, err := c.asset(hub); err == nil {
+			hubs[id] = true
+		}
+	}
+
+	maxLegs := c.MaxLegs
+	if maxLegs <= 0 {
+		maxLegs = 1
+	}
+
+	for legs := 1; legs <= maxLegs; legs++ {
+		var best []ConversionLeg
+		bestResult := 0.0
+		c.walk(fromID, toID, legs, hubs, map[string]bool{fromID: true}, nil, amount, source, func(path []ConversionLeg, result float64) {
+			if best == nil || result > bestResult {
+				best = append([]ConversionLeg(nil), path...)
+				bestResult = result
+			}
+		})
+		if best != nil {
+			conversion.Path = best
+			conversion.Result = bestResult
+			return conversion, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no conversion path from %s to %s within %d legs", from, to, maxLegs)
+}
+
+// walk calls found for every path of exactly legs legs from node to target passing through hubs only
+func (c *Converter) walk(node, target string, legs int, hubs, visited map[string]bool, path []ConversionLeg,
+	amount float64, source PriceSource, found func(path []ConversionLeg, result float64)) {
+	for _, edge := range c.edges[node] {
+		if visited[edge.to] {
+			continue
+		}
+		last := legs == 1
+		if last != (edge.to == target) || (!last && !hubs[edge.to]) {
+			continue
+		}
+
+		price := edgePrice(edge.ticker, source)
+		if price <= 0 {
+			continue
+		}
+		next := amount * price
+		if edge.inverse {
+			next = amount / price
+		}
+
+		leg := ConversionLeg{Pair: edge.pair, From: node, To: edge.to, Price: price, Inverse: edge.inverse}
+		if last {
+			found(append(path, leg), next)
+			continue
+		}
+
+		visited[edge.to] = true
+		c.walk(edge.to, target, legs-1, hubs, visited, append(path, leg), next, source, found)
+		delete(visited, edge.to)
+	}
+}
+
+func edgePrice(ticker *TickerValues, source PriceSource) float64 {
+	switch source {
+	case PriceBid:
+		return ticker.Bid.Price.Float64()
+	case PriceAsk:
+		return ticker.Ask.Price.Float64()
+	}
+	return ticker.Mid()
+}
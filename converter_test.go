@@ -0,0 +1,55 @@
+package krakenapi
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConverter(t *testing.T) {
+	pairs := &AssetPairs{
+		"XXBTZEUR": {Base: "XXBT", Quote: "ZEUR", WSName: "XBT/EUR"},
+		"XXBTZUSD": {Base: "XXBT", Quote: "ZUSD", WSName: "XBT/USD"},
+		"DOTXBT":   {Base: "DOT", Quote: "XXBT", WSName: "DOT/XBT"},
+		"DOTUSD":   {Base: "DOT", Quote: "ZUSD", WSName: "DOT/USD"},
+		"ZEURZUSD": {Base: "ZEUR", Quote: "ZUSD", WSName: "EUR/USD"},
+		"XXRPZJPY": {Base: "XXRP", Quote: "ZJPY", WSName: "XRP/JPY"},
+	}
+	ticker := func(bid, ask float64) *TickerValues {
+		return &TickerValues{Bid: TickerLevel{Price: DecimalFromFloat(bid)}, Ask: TickerLevel{Price: DecimalFromFloat(ask)}}
+	}
+	snapshot := &MarketSnapshot{
+		Time: time.Now().Add(-time.Minute),
+		Tickers: map[string]*TickerValues{
+			"XXBTZEUR": ticker(19990, 20010),
+			"XXBTZUSD": ticker(21990, 22010),
+			"DOTXBT":   ticker(0.00024, 0.00026),
+			"DOTUSD":   ticker(5.4, 5.6),
+			"ZEURZUSD": ticker(1.09, 1.11),
+			"XXRPZJPY": ticker(70, 71),
+		},
+	}
+	c := NewConverter(pairs, snapshot)
+
+	direct, err := c.Convert(2, "BTC", "EUR", PriceBid)
+	if err != nil || direct.Result != 39980 || len(direct.Path) != 1 {
+		t.Errorf("Convert(BTC, EUR) should use the direct pair, got %+v %v", direct, err)
+	}
+	if direct.Age < time.Minute {
+		t.Errorf("Convert() should return the age of the prices, got %s", direct.Age)
+	}
+
+	inverse, err := c.Convert(20000, "ZEUR", "XXBT", PriceMid)
+	if err != nil || !almostEqual(inverse.Result, 1) || !inverse.Path[0].Inverse {
+		t.Errorf("Convert(EUR, BTC) should use the inverse pair, got %+v %v", inverse, err)
+	}
+
+	// DOT -> EUR has no direct pair, via XBT gives 0.00025 * 20000 = 5, via USD gives 5.5 / 1.1 = 5
+	hop, err := c.Convert(10, "DOT", "EUR", PriceMid)
+	if err != nil || len(hop.Path) != 2 || !almostEqual(hop.Result, 50) {
+		t.Errorf("Convert(DOT, EUR) should hop through a hub, got %+v %v", hop, err)
+	}
+
+	if _, err := c.Convert(1, "DOT", "JPY", PriceMid); err == nil {
+		t.Errorf("Convert(DOT, JPY) should fail without a path")
+	}
+}
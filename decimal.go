@@ -0,0 +1,108 @@
+package krakenapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+// Decimal is a decimal number as sent by Kraken. It keeps the exact text next to its float64 value,
+// so prices and volumes can be passed back to the API or compared without float rounding.
+type Decimal struct {
+	text  string
+	value float64
+}
+
+// ParseDecimal parses a decimal number string such as "-12.345" or "1.5e-8".
+// NaN, infinities, hexadecimal numbers and fractions are rejected, so Rat never fails.
+func ParseDecimal(s string) (Decimal, error) {
+	if !isDecimal(s) {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	if _, ok := new(big.Rat).SetString(s); !ok {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return Decimal{}, err
+	}
+	return Decimal{text: s, value: value}, nil
+}
+
+// isDecimal returns true if s is a signed decimal number with an optional fraction and exponent
+func isDecimal(s string) bool {
+	i := 0
+	digits := func() int {
+		start := i
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		return i - start
+	}
+	if i < len(s) && (s[i] == '+' || s[i] == '-') {
+		i++
+	}
+	n := digits()
+	if i < len(s) && s[i] == '.' {
+		i++
+		n += digits()
+	}
+	if n == 0 {
+		return false
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		if i < len(s) && (s[i] == '+' || s[i] == '-') {
+			i++
+		}
+		if digits() == 0 {
+			return false
+		}
+	}
+	return i == len(s)
+}
+
+// DecimalFromFloat returns the Decimal of f, written with as few digits as needed.
+// NaN and infinities have no exact value, Rat returns nil for them.
+func DecimalFromFloat(f float64) Decimal {
+	return Decimal{text: strconv.FormatFloat(f, 'f', -1, 64), value: f}
+}
+
+// Float64 returns the nearest float64 of the decimal
+func (d Decimal) Float64() float64 {
+	return d.value
+}
+
+// String returns the decimal exactly as Kraken sent it
+func (d Decimal) String() string {
+	if d.text == "" {
+		return "0"
+	}
+	return d.text
+}
+
+// Rat returns the exact value of the decimal, nil only if it was made from a NaN or infinite float
+func (d Decimal) Rat() *big.Rat {
+	r, _ := new(big.Rat).SetString(d.String())
+	return r
+}
+
+// MarshalJSON writes the decimal as a JSON string, like Kraken does
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON reads the decimal from a JSON string or number
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		text = string(data)
+	}
+	parsed, err := ParseDecimal(text)
+	if err != nil {
+		return fmt.Errorf("invalid decimal %s", data)
+	}
+	*d = parsed
+	return nil
+}
@@ -0,0 +1,41 @@
+package krakenapi
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestDecimal(t *testing.T) {
+	d, err := ParseDecimal("0.10000000")
+	if err != nil {
+		t.Fatalf("ParseDecimal() should not return an error, got %s", err)
+	}
+	if d.String() != "0.10000000" || d.Float64() != 0.1 {
+		t.Errorf("ParseDecimal() should keep the text and value, got %s %v", d, d.Float64())
+	}
+	if d.Rat().Cmp(big.NewRat(1, 10)) != 0 {
+		t.Errorf("Rat() should return the exact value, got %s", d.Rat())
+	}
+	for _, invalid := range []string{"", "1e", ".", "-", "NaN", "Inf", "-Infinity", "0x1p-2", "1/3", "1_000", "1e400"} {
+		if _, err := ParseDecimal(invalid); err == nil {
+			t.Errorf("ParseDecimal(%q) should fail", invalid)
+		}
+	}
+	for _, valid := range []string{"-12.345", "+1", ".5", "5.", "1.5e-8", "2E3"} {
+		if d, err := ParseDecimal(valid); err != nil || d.Rat() == nil {
+			t.Errorf("ParseDecimal(%q) should return a decimal with an exact value, got %v", valid, err)
+		}
+	}
+	if s := DecimalFromFloat(0.00000001).String(); s != "0.00000001" {
+		t.Errorf("DecimalFromFloat() should not use scientific notation, got %s", s)
+	}
+
+	var values []Decimal
+	if err := json.Unmarshal([]byte(`["6389.40000", 2.5]`), &values); err != nil || values[0].String() != "6389.40000" || values[1].Float64() != 2.5 {
+		t.Errorf("UnmarshalJSON() should read strings and numbers, got %v %v", values, err)
+	}
+	if data, _ := json.Marshal(values[0]); string(data) != `"6389.40000"` {
+		t.Errorf("MarshalJSON() should write the exact text, got %s", data)
+	}
+}
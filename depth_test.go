@@ -0,0 +1,72 @@
+package krakenapi
+
+import (
+	"context"
+	"math"
+	"net/url"
+	"testing"
+)
+
+func TestDepthMulti(t *testing.T) {
+	api := newTestAPI(func(method string, values url.Values) string {
+		switch values.Get("pair") {
+		case "XBTEUR":
+			return `{"error":[],"result":{"XXBTZEUR":{"asks":[["20001.0","1.5",1688671968]],"bids":[["20000.0","2.5",1688671968]]}}}`
+		case XETHZEUR:
+			return `{"error":[],"result":{"XETHZEUR":{"asks":[["1801.0","10",1688671968]],"bids":[["1800.0","20",1688671968]]}}}`
+		}
+		return `{"error":["EQuery:Unknown asset pair"]}`
+	})
+
+	books, err := api.Public().DepthMulti(context.Background(), []string{"XBTEUR", XETHZEUR, "FOOBAR"}, 10)
+
+	errs, ok := err.(DepthErrors)
+	if !ok || len(errs) != 1 || errs["FOOBAR"] == nil {
+		t.Errorf("DepthMulti() should return the error of the failed pair, got %v", err)
+	}
+	if len(books) != 2 || books["XBTEUR"].Mid() != 20000.5 || books[XETHZEUR].Bids[0].Amount != 20 {
+		t.Errorf("DepthMulti() should return the books of the other pairs by given name, got %+v", books)
+	}
+}
+
+func TestDepthMultiCancelled(t *testing.T) {
+	client := newTestClient(func(method string, values url.Values) string {
+		return `{"error":[],"result":{}}`
+	})
+	api := NewWithLimiter("", "", client, NewRateLimiter(0.001, 1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	books, err := api.Public().DepthMulti(ctx, []string{XXBTZEUR, XETHZEUR}, 10)
+	if errs, ok := err.(DepthErrors); !ok || errs[XXBTZEUR] != context.Canceled || len(books) != 0 {
+		t.Errorf("DepthMulti() should fail every pair when cancelled, got %v %v", books, err)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := NewRateLimiter(0.001, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	if limiter.Wait(ctx) != nil || limiter.Wait(ctx) != nil {
+		t.Errorf("Wait() should allow bursts without waiting")
+	}
+
+	cancel()
+	if err := limiter.Wait(ctx); err != context.Canceled {
+		t.Errorf("Wait() should return when the context is done, got %v", err)
+	}
+}
+
+func TestNewRateLimiterInvalidRate(t *testing.T) {
+	for _, rate := range []float64{0, -1, math.NaN()} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewRateLimiter(%v) should panic", rate)
+				}
+			}()
+			NewRateLimiter(rate, 1)
+		}()
+	}
+}
@@ -0,0 +1,173 @@
+package krakenapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FeeRates are the maker and taker fees of a pair in percent
+type FeeRates struct {
+	Maker float64
+	Taker float64
+}
+
+// FeeTier is a tier of a pair's fee schedule
+type FeeTier struct {
+	// 30 day volume from which the tier applies, in the pair's fee volume currency
+	Volume float64
+	FeeRates
+}
+
+// FeeCalculator computes the fees of orders from the fee schedules of the pairs and the 30 day volume
+// of the account. Rates reported by TradeVolume for a pair take precedence over its schedule.
+// Pairs are given in any form known to a PairResolver.
+type FeeCalculator struct {
+	pairs  *PairResolver
+	volume float64
+	taker  Fees
+	maker  Fees
+}
+
+// NewFeeCalculator creates a FeeCalculator from the asset pairs and the account's trade volume,
+// as returned by TradeVolume with fee-info set to true
+func NewFeeCalculator(pairs AssetPairsResponse, volume *TradeVolumeResponse) *FeeCalculator {
+	c := &FeeCalculator{pairs: NewPairResolver(pairs)}
+	if volume != nil {
+		c.volume, c.taker, c.maker = volume.Volume, volume.Fees, volume.FeesMaker
+	}
+	return c
+}
+
+// LoadFeeCalculator creates a FeeCalculator from the asset pairs and the account's trade volume queried from the API,
+// with the fee rates reported for the given pairs
+func LoadFeeCalculator(api API, pairs ...string) (*FeeCalculator, error) {
+	assetPairs, err := api.Public().AssetPairs(pairs...)
+	if err != nil {
+		return nil, err
+	}
+	args := map[string]string{"fee-info": "true"}
+	if len(pairs) > 0 {
+		args["pair"] = strings.Join(pairs, ",")
+	}
+	volume, err := api.Private().TradeVolume(args)
+	if err != nil {
+		return nil, err
+	}
+	return NewFeeCalculator(assetPairs, volume), nil
+}
+
+// Volume returns the 30 day volume of the account
+func (c *FeeCalculator) Volume() float64 {
+	return c.volume
+}
+
+// Rates returns the current maker and taker fees of pair
+func (c *FeeCalculator) Rates(pair string) (FeeRates, error) {
+	name, info, err := c.info(pair)
+	if err != nil {
+		return FeeRates{}, err
+	}
+
+	rates := ratesAt(info, c.volume)
+	if fee, ok := c.reportedFee(c.taker, name); ok {
+		rates.Taker = fee.Fee
+		if c.maker == nil {
+			rates.Maker = fee.Fee
+		}
+	}
+	if fee, ok := c.reportedFee(c.maker, name); ok {
+		rates.Maker = fee.Fee
+	}
+	return rates, nil
+}
+
+// NextTier returns the next tier of pair's fee schedule above the account's volume, nil at the last tier
+func (c *FeeCalculator) NextTier(pair string) (*FeeTier, error) {
+	_, info, err := c.info(pair)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tier := range info.Fees {
+		if len(tier) > 1 && tier[0] > c.volume {
+			return &FeeTier{Volume: tier[0], FeeRates: ratesAt(info, tier[0])}, nil
+		}
+	}
+	return nil, nil
+}
+
+// Fee returns the fee in quote currency of an order of volume at price, maker for orders adding liquidity
+func (c *FeeCalculator) Fee(pair string, volume, price float64, maker bool) (float64, error) {
+	rates, err := c.Rates(pair)
+	if err != nil {
+		return 0, err
+	}
+	rate := rates.Taker
+	if maker {
+		rate = rates.Maker
+	}
+	return volume * price * rate / 100, nil
+}
+
+// NetProceeds returns the quote currency received by a sell, or spent by a buy as a negative amount,
+// of volume at price after the fee
+func (c *FeeCalculator) NetProceeds(pair, direction string, volume, price float64, maker bool) (float64, error) {
+	fee, err := c.Fee(pair, volume, price, maker)
+	if err != nil {
+		return 0, err
+	}
+	switch direction {
+	case "buy":
+		return -(volume*price + fee), nil
+	case "sell":
+		return volume*price - fee, nil
+	}
+	return 0, fmt.Errorf("Unsupported direction: %s", direction)
+}
+
+// info returns the canonical name and the asset pair information of pair
+func (c *FeeCalculator) info(pair string) (string, AssetPairInfo, error) {
+	name, err := c.pairs.Resolve(pair)
+	if err != nil {
+		return "", AssetPairInfo{}, err
+	}
+	info, err := c.pairs.Info(name)
+	return name, info, err
+}
+
+// reportedFee returns the fee TradeVolume reported for the pair of canonical name, in whichever form it names the pair
+func (c *FeeCalculator) reportedFee(fees Fees, name string) (FeeInfo, bool) {
+	if fees == nil {
+		return FeeInfo{}, false
+	}
+	for _, reported := range fees.GetPairs() {
+		if c.pairs.resolveOrKeep(reported) == name {
+			return fees.GetFeeInfo(reported), true
+		}
+	}
+	return FeeInfo{}, false
+}
+
+// ratesAt returns the rates of info's fee schedule at volume. Pairs without a maker schedule charge the taker fee.
+func ratesAt(info AssetPairInfo, volume float64) FeeRates {
+	rates := FeeRates{Taker: scheduleRate(info.Fees, volume)}
+	rates.Maker = rates.Taker
+	if len(info.FeesMaker) > 0 {
+		rates.Maker = scheduleRate(info.FeesMaker, volume)
+	}
+	return rates
+}
+
+// scheduleRate returns the percent fee of the highest tier of schedule reached by volume
+func scheduleRate(schedule [][]float64, volume float64) float64 {
+	var rate float64
+	for i, tier := range schedule {
+		if len(tier) < 2 {
+			continue
+		}
+		if i == 0 || tier[0] <= volume {
+			rate = tier[1]
+		}
+	}
+	return rate
+}
@@ -0,0 +1,74 @@
+package krakenapi
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestFeeCalculator(t *testing.T) {
+	api := newTestAPI(func(method string, values url.Values) string {
+		switch method {
+		case "AssetPairs":
+			return `{"error":[],"result":{` +
+				`"XXBTZEUR":{"altname":"XBTEUR","wsname":"XBT/EUR","base":"XXBT","quote":"ZEUR",` +
+				`"fees":[[0,0.26],[50000,0.24],[100000,0.22]],"fees_maker":[[0,0.16],[50000,0.14],[100000,0.12]]},` +
+				`"XETHZEUR":{"altname":"ETHEUR","base":"XETH","quote":"ZEUR",` +
+				`"fees":[[0,0.26],[50000,0.24],[100000,0.22]],"fees_maker":[[0,0.16],[50000,0.14],[100000,0.12]]}}}`
+		case "TradeVolume":
+			if values.Get("pair") != "XXBTZEUR" || values.Get("fee-info") != "true" {
+				t.Errorf("TradeVolume() should ask for the fees of XXBTZEUR, got %v", values)
+			}
+			return `{"error":[],"result":{"currency":"ZUSD","volume":"60000.0000",` +
+				`"fees":{"XXBTZEUR":{"fee":"0.2000","minfee":"0.1000","maxfee":"0.2600","nextfee":"0.2200","nextvolume":"100000.0000","tiervolume":"50000.0000"}},` +
+				`"fees_maker":{"XXBTZEUR":{"fee":"0.1000","minfee":"0.0000","maxfee":"0.1600","nextfee":null,"nextvolume":null,"tiervolume":"50000.0000"}}}}`
+		}
+		return `{"error":["EGeneral:Unknown method"]}`
+	})
+
+	c, err := LoadFeeCalculator(api, "XXBTZEUR")
+	if err != nil {
+		t.Fatalf("LoadFeeCalculator() should not return an error, got %s", err)
+	}
+	if c.Volume() != 60000 {
+		t.Errorf("Volume() should return 60000, got %v", c.Volume())
+	}
+
+	if rates, _ := c.Rates("XXBTZEUR"); rates.Maker != 0.1 || rates.Taker != 0.2 {
+		t.Errorf("Rates() should prefer the rates reported by TradeVolume, got %+v", rates)
+	}
+	for _, pair := range []string{"XBTEUR", "XBT/EUR", "BTC/EUR"} {
+		if rates, err := c.Rates(pair); err != nil || rates.Maker != 0.1 || rates.Taker != 0.2 {
+			t.Errorf("Rates(%s) should resolve the pair, got %+v %v", pair, rates, err)
+		}
+	}
+	if rates, _ := c.Rates("XETHZEUR"); rates.Maker != 0.14 || rates.Taker != 0.24 {
+		t.Errorf("Rates() should use the schedule tier of the account volume, got %+v", rates)
+	}
+	if _, err := c.Rates("XXRPZJPY"); err == nil {
+		t.Errorf("Rates() should fail for an unknown pair")
+	}
+
+	next, _ := c.NextTier("ETHEUR")
+	if next == nil || next.Volume != 100000 || next.Maker != 0.12 || next.Taker != 0.22 {
+		t.Errorf("NextTier() should return the 100000 tier, got %+v", next)
+	}
+
+	if fee, _ := c.Fee("XXBTZEUR", 0.5, 20000, false); !almostEqual(fee, 20) {
+		t.Errorf("Fee() should return 20 for a taker order of 10000, got %v", fee)
+	}
+	if proceeds, _ := c.NetProceeds("XXBTZEUR", "sell", 0.5, 20000, true); !almostEqual(proceeds, 9990) {
+		t.Errorf("NetProceeds() should return 9990 for a maker sell, got %v", proceeds)
+	}
+	if proceeds, _ := c.NetProceeds("XXBTZEUR", "buy", 0.5, 20000, false); !almostEqual(proceeds, -10020) {
+		t.Errorf("NetProceeds() should return -10020 for a taker buy, got %v", proceeds)
+	}
+	if _, err := c.NetProceeds("XXBTZEUR", "hold", 0.5, 20000, false); err == nil {
+		t.Errorf("NetProceeds() should fail for an unknown direction")
+	}
+
+	pairs, _ := api.Public().AssetPairs()
+	top := NewFeeCalculator(pairs, &TradeVolumeResponse{Volume: 250000})
+	if next, err := top.NextTier("XETHZEUR"); next != nil || err != nil {
+		t.Errorf("NextTier() should return nil at the last tier, got %+v %v", next, err)
+	}
+}
@@ -0,0 +1,78 @@
+// Package indicators computes technical indicators over Kraken OHLC candles.
+//
+// Every indicator is streaming: it is fed one candle at a time with Update, so it works the same
+// over a backfill from krakenapi.OHLCIterator and over a live feed. The Series functions are the
+// batch versions, they run an indicator over a whole []*OHLC and return one value per candle,
+// NaN while the indicator warms up.
+package indicators
+
+import (
+	"math"
+
+	krakenapi "github.com/beldur/kraken-go-api-client"
+)
+
+// Indicator is a single valued indicator
+type Indicator interface {
+	// Update adds the next candle and returns the indicator value, ok is false while it warms up
+	Update(c *krakenapi.OHLC) (value float64, ok bool)
+}
+
+// Series runs the indicator over candles and returns its value for each candle, NaN while it warms up
+func Series(indicator Indicator, candles []*krakenapi.OHLC) []float64 {
+	values := make([]float64, len(candles))
+	for i, c := range candles {
+		value, ok := indicator.Update(c)
+		if !ok {
+			value = math.NaN()
+		}
+		values[i] = value
+	}
+	return values
+}
+
+// validPeriod returns period, at least 1
+func validPeriod(period int) int {
+	if period < 1 {
+		return 1
+	}
+	return period
+}
+
+// window is a fixed size ring buffer of the last values
+type window struct {
+	values []float64
+	next   int
+	full   bool
+}
+
+func newWindow(size int) *window {
+	return &window{values: make([]float64, validPeriod(size))}
+}
+
+// push adds value and returns the value it replaced, if the window was full
+func (w *window) push(value float64) (float64, bool) {
+	old, full := w.values[w.next], w.full
+	w.values[w.next] = value
+	w.next++
+	if w.next == len(w.values) {
+		w.next, w.full = 0, true
+	}
+	return old, full
+}
+
+// at returns the i-th value, oldest first
+func (w *window) at(i int) float64 {
+	if !w.full {
+		return w.values[i]
+	}
+	return w.values[(w.next+i)%len(w.values)]
+}
+
+// len returns the number of values in the window
+func (w *window) len() int {
+	if w.full {
+		return len(w.values)
+	}
+	return w.next
+}
@@ -0,0 +1,190 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	krakenapi "github.com/beldur/kraken-go-api-client"
+)
+
+// Closes of the moving average examples of StockCharts ChartSchool.
+// The golden values were computed independently from the textbook definitions of each indicator.
+var testCloses = []float64{22.27, 22.19, 22.08, 22.17, 22.18, 22.13, 22.23, 22.43, 22.24, 22.29, 22.15, 22.39, 22.38, 22.61, 23.36, 24.05, 23.75, 23.83, 23.95, 23.63, 23.82, 23.87, 23.65, 23.19, 23.10, 23.33, 22.68, 23.10, 22.40, 22.17}
+
+var nan = math.NaN()
+
+var golden = map[string][]float64{
+	"sma10":  {nan, nan, nan, nan, nan, nan, nan, nan, nan, 22.221000, 22.209000, 22.229000, 22.259000, 22.303000, 22.421000, 22.613000, 22.765000, 22.905000, 23.076000, 23.210000, 23.377000, 23.525000, 23.652000, 23.710000, 23.684000, 23.612000, 23.505000, 23.432000, 23.277000, 23.131000},
+	"ema10":  {nan, nan, nan, nan, nan, nan, nan, nan, nan, 22.221000, 22.208091, 22.241165, 22.266408, 22.328879, 22.516356, 22.795200, 22.968800, 23.125382, 23.275312, 23.339801, 23.427110, 23.507635, 23.533520, 23.471062, 23.403596, 23.390215, 23.261085, 23.231797, 23.080561, 22.915004},
+	"wma10":  {nan, nan, nan, nan, nan, nan, nan, nan, nan, 22.242909, 22.230000, 22.262909, 22.290364, 22.354182, 22.546364, 22.842545, 23.049273, 23.242909, 23.432909, 23.533636, 23.644545, 23.734182, 23.756909, 23.672909, 23.562000, 23.497636, 23.328182, 23.254545, 23.066909, 22.865636},
+	"rsi14":  {nan, nan, nan, nan, nan, nan, nan, nan, nan, nan, nan, nan, nan, nan, 74.222222, 80.621948, 72.226060, 73.032563, 74.240867, 65.777084, 68.102330, 68.704929, 63.059969, 53.214759, 51.519968, 55.426826, 44.510078, 51.198546, 42.092141, 39.599857},
+	"macd":   {nan, nan, nan, nan, nan, nan, nan, nan, nan, 0.047420, 0.020856, 0.041466, 0.048679, 0.084512, 0.212572, 0.374085, 0.394057, 0.393189, 0.387068, 0.311786, 0.280615, 0.254181, 0.191024, 0.075301, -0.006021, -0.015165, -0.117718, -0.102886, -0.194620, -0.267711},
+	"signal": {nan, nan, nan, nan, nan, nan, nan, nan, nan, nan, nan, nan, 0.039605, 0.057568, 0.119570, 0.221376, 0.290448, 0.331545, 0.353754, 0.336967, 0.314426, 0.290328, 0.250607, 0.180484, 0.105882, 0.057464, -0.012609, -0.048720, -0.107080, -0.171332},
+}
+
+func testCandles() []*krakenapi.OHLC {
+	return referenceCandles(testCloses, testCloses, testCloses, nil)
+}
+
+// referenceCandles returns daily candles of the columns of an example table, volumes may be nil
+func referenceCandles(highs, lows, closes, volumes []float64) []*krakenapi.OHLC {
+	candles := make([]*krakenapi.OHLC, len(closes))
+	for i := range closes {
+		candles[i] = &krakenapi.OHLC{
+			Time:  time.Unix(int64(i)*86400, 0),
+			High:  highs[i],
+			Low:   lows[i],
+			Close: closes[i],
+		}
+		if volumes != nil {
+			candles[i].Volume = volumes[i]
+		}
+	}
+	return candles
+}
+
+func checkSeries(t *testing.T, name string, values []float64) {
+	t.Helper()
+	expected := golden[name]
+	if len(values) != len(expected) {
+		t.Fatalf("%s should return %d values, got %d", name, len(expected), len(values))
+	}
+	for i := range expected {
+		if math.IsNaN(expected[i]) != math.IsNaN(values[i]) || math.Abs(values[i]-expected[i]) > 1e-6 {
+			t.Errorf("%s[%d] should be %v, got %v", name, i, expected[i], values[i])
+		}
+	}
+}
+
+// checkReference compares the values from index first on with rounded expected values
+func checkReference(t *testing.T, name string, values []float64, first int, expected []float64, tolerance float64) {
+	t.Helper()
+	if len(values) != first+len(expected) {
+		t.Fatalf("%s should return %d values, got %d", name, first+len(expected), len(values))
+	}
+	for i := 0; i < first; i++ {
+		if !math.IsNaN(values[i]) {
+			t.Errorf("%s[%d] should not be ready, got %v", name, i, values[i])
+		}
+	}
+	for i, value := range expected {
+		if math.Abs(values[first+i]-value) > tolerance {
+			t.Errorf("%s[%d] should be %v, got %v", name, first+i, value, values[first+i])
+		}
+	}
+}
+
+func TestMovingAverages(t *testing.T) {
+	candles := testCandles()
+	checkSeries(t, "sma10", SMASeries(candles, 10))
+	checkSeries(t, "ema10", EMASeries(candles, 10))
+	checkSeries(t, "wma10", WMASeries(candles, 10))
+}
+
+func TestMomentum(t *testing.T) {
+	candles := testCandles()
+	checkSeries(t, "rsi14", RSISeries(candles, 14))
+
+	var macd, signal, histogram []float64
+	for _, value := range MACDSeries(candles, 5, 10, 4) {
+		macd = append(macd, value.MACD)
+		signal = append(signal, value.Signal)
+		histogram = append(histogram, value.Histogram)
+	}
+	checkSeries(t, "macd", macd)
+	checkSeries(t, "signal", signal)
+	for i := range histogram {
+		if !math.IsNaN(histogram[i]) && math.Abs(histogram[i]-(macd[i]-signal[i])) > 1e-12 {
+			t.Errorf("MACD histogram should be MACD minus signal, got %v", histogram[i])
+		}
+	}
+}
+
+func TestRSIReference(t *testing.T) {
+	// Wilder's RSI example of StockCharts ChartSchool
+	closes := []float64{44.34, 44.09, 44.15, 43.61, 44.33, 44.83, 45.10, 45.42, 45.84, 46.08, 45.89, 46.03, 45.61, 46.28, 46.28, 46.00, 46.03, 46.41, 46.22, 45.64, 46.21, 46.25, 45.71, 46.45, 45.78, 45.35, 44.03, 44.18, 44.22, 44.57, 43.42, 42.66, 43.13}
+	expected := []float64{70.46, 66.25, 66.48, 69.35, 66.29, 57.92, 62.88, 63.21, 56.01, 62.34, 54.67, 50.39, 40.02, 41.49, 41.90, 45.50, 37.32, 33.09, 37.79}
+
+	rsi := NewRSI(14)
+	for i, close := range closes {
+		value, ok := rsi.Update(&krakenapi.OHLC{Close: close})
+		if ok != (i >= 14) {
+			t.Fatalf("Update() should be ready after 15 closes, got %v at %d", ok, i)
+		}
+		if ok && math.Abs(value-expected[i-14]) > 0.005 {
+			t.Errorf("RSI at %d should be %v, got %v", i, expected[i-14], value)
+		}
+	}
+}
+
+func TestStochastic(t *testing.T) {
+	// Highs, lows and closes of the stochastic oscillator example of StockCharts ChartSchool as input only.
+	// The expected values are not the published ones, they were computed independently with the textbook definition.
+	highs := []float64{127.01, 127.62, 126.59, 127.35, 128.17, 128.43, 127.37, 126.42, 126.90, 126.85, 125.65, 125.72, 127.16, 127.72, 127.69, 128.22, 128.27, 128.09, 128.27, 127.74, 128.77, 129.29, 130.06, 129.12, 129.29, 128.47, 128.09, 128.65, 129.14, 128.64}
+	lows := []float64{125.36, 126.16, 124.93, 126.09, 126.82, 126.48, 126.03, 124.83, 126.39, 125.72, 124.56, 124.57, 125.07, 126.86, 126.63, 126.80, 126.71, 126.80, 126.13, 125.92, 126.99, 127.81, 128.47, 128.06, 127.61, 127.60, 127.00, 126.90, 127.49, 127.40}
+	closes := []float64{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 127.2876, 127.1781, 128.0138, 127.1085, 127.7253, 127.0587, 127.3273, 128.7103, 127.8745, 128.5809, 128.6008, 127.9342, 128.1133, 127.5960, 127.5960, 128.6904, 128.2725}
+	expectedK := []float64{70.48, 67.65, 89.25, 65.85, 81.79, 64.57, 74.59, 98.58, 70.07, 73.11, 73.47, 61.28, 60.99, 40.48, 40.48, 66.92, 56.82}
+	expectedD := []float64{75.79, 74.25, 78.96, 70.74, 73.65, 79.25, 81.08, 80.59, 72.22, 69.29, 65.25, 54.25, 47.32, 49.29, 54.74}
+
+	var k, d []float64
+	for _, value := range StochasticSeries(referenceCandles(highs, lows, closes, nil), 14, 3) {
+		k = append(k, value.K)
+		d = append(d, value.D)
+	}
+	checkReference(t, "k", k, 13, expectedK, 0.005)
+	checkReference(t, "d", d, 15, expectedD, 0.005)
+}
+
+func TestBollinger(t *testing.T) {
+	// Closes of the Bollinger Bands example of StockCharts ChartSchool as input only, 20 days at 2 standard deviations.
+	// The expected values are not the published ones, they were computed independently with the population standard deviation.
+	closes := []float64{86.1557, 89.0867, 88.7829, 90.3228, 89.0671, 91.1453, 89.4397, 89.1750, 86.9302, 87.6752, 86.9596, 89.4299, 89.3221, 88.7241, 87.4497, 87.2634, 89.4985, 87.9006, 89.1260, 90.7043, 92.9001, 92.9784, 91.8021, 92.6647, 92.6843, 92.3021, 92.7725, 92.5373, 92.9490, 93.2039, 91.0669, 89.8318, 89.7435, 90.3994, 90.7387, 88.0177, 88.0867, 88.8439, 90.7781, 90.5416, 91.3894, 90.6500}
+	expectedMiddle := []float64{88.71, 89.05, 89.24, 89.39, 89.51, 89.69, 89.75, 89.91, 90.08, 90.38, 90.66, 90.86, 90.88, 90.91, 90.99, 91.15, 91.19, 91.12, 91.17, 91.25, 91.24, 91.17, 91.05}
+	expectedUpper := []float64{91.29, 91.95, 92.61, 92.93, 93.31, 93.73, 93.90, 94.27, 94.57, 94.79, 95.04, 94.91, 94.90, 94.90, 94.86, 94.67, 94.56, 94.68, 94.58, 94.53, 94.53, 94.37, 94.15}
+	expectedLower := []float64{86.12, 86.14, 85.87, 85.85, 85.70, 85.65, 85.59, 85.56, 85.60, 85.98, 86.27, 86.82, 86.87, 86.91, 87.12, 87.63, 87.83, 87.56, 87.76, 87.97, 87.95, 87.96, 87.95}
+
+	var upper, lower, middle []float64
+	for _, value := range BollingerSeries(referenceCandles(closes, closes, closes, nil), 20, 2) {
+		upper = append(upper, value.Upper)
+		lower = append(lower, value.Lower)
+		middle = append(middle, value.Middle)
+	}
+	checkReference(t, "middle", middle, 19, expectedMiddle, 0.005)
+	checkReference(t, "upper", upper, 19, expectedUpper, 0.005)
+	checkReference(t, "lower", lower, 19, expectedLower, 0.005)
+}
+
+func TestATR(t *testing.T) {
+	// Highs, lows and closes of the average true range example of StockCharts ChartSchool as input only, QQQ through the May 2010 crash.
+	// The expected values are not the published ones, they were computed independently with Wilder's definition.
+	highs := []float64{48.70, 48.72, 48.90, 48.87, 48.82, 49.05, 49.20, 49.35, 49.92, 50.19, 50.12, 49.66, 49.88, 50.19, 50.36, 50.57, 50.65, 50.43, 49.63, 50.33, 50.29, 50.17, 49.32, 48.50, 48.32, 46.80, 47.80, 48.39, 48.66, 48.79}
+	lows := []float64{47.79, 47.14, 48.39, 48.37, 48.24, 48.64, 48.94, 48.86, 49.50, 49.87, 49.20, 48.90, 49.43, 49.73, 49.26, 50.09, 50.30, 49.21, 48.98, 49.61, 49.20, 49.43, 48.08, 47.64, 41.55, 44.28, 47.31, 47.20, 47.90, 47.73}
+	closes := []float64{48.16, 48.61, 48.75, 48.63, 48.74, 49.03, 49.07, 49.32, 49.91, 50.13, 49.53, 49.50, 49.75, 50.03, 50.31, 50.52, 50.41, 49.34, 49.37, 50.23, 49.24, 49.93, 48.43, 48.18, 46.57, 45.41, 47.77, 47.72, 48.62, 47.85}
+	expected := []float64{0.6257, 0.6596, 0.6468, 0.6256, 0.6680, 0.6667, 0.6877, 0.7164, 0.7317, 0.8116, 0.8150, 1.2404, 1.3318, 1.4074, 1.3918, 1.3596, 1.3382}
+
+	checkReference(t, "atr14", ATRSeries(referenceCandles(highs, lows, closes, nil), 14), 13, expected, 5e-5)
+}
+
+func TestVolume(t *testing.T) {
+	// On-balance volume example of Investopedia, whose published OBV starts at 0
+	closes := []float64{10, 10.15, 10.17, 10.13, 10.11, 10.15, 10.20, 10.20, 10.22, 10.21}
+	volumes := []float64{25200, 30000, 25600, 32000, 23000, 40000, 36000, 20500, 23000, 27500}
+	candles := referenceCandles(closes, closes, closes, volumes)
+	checkReference(t, "obv", OBVSeries(candles), 0, []float64{0, 30000, 55600, 23600, 600, 40600, 76600, 76600, 99600, 72100}, 0)
+
+	// With high, low and close equal the VWAP is the volume weighted mean of the closes
+	checkReference(t, "vwap", VWAPSeries(candles, 0), 0, []float64{10.0000, 10.0815, 10.1096, 10.1154, 10.1144, 10.1225, 10.1357, 10.1414, 10.1485, 10.1544}, 5e-5)
+	checkReference(t, "vwap5", VWAPSeries(candles, 5), 4, []float64{10.1144, 10.1430, 10.1548, 10.1583, 10.1747, 10.1914}, 5e-5)
+
+	// Candles are weighted at their own VWAP when Kraken reports one
+	vwap := NewVWAP(0)
+	vwap.Update(&krakenapi.OHLC{High: 12, Low: 8, Close: 10, Vwap: 9, Volume: 1})
+	if value, _ := vwap.Update(&krakenapi.OHLC{High: 12, Low: 8, Close: 10, Vwap: 12, Volume: 2}); value != 11 {
+		t.Errorf("VWAP should weight candles at their VWAP, got %v", value)
+	}
+	vwap.Reset()
+	if _, ok := vwap.Update(&krakenapi.OHLC{Close: 10}); ok {
+		t.Errorf("VWAP should not be ready after Reset() without volume")
+	}
+}
@@ -0,0 +1,181 @@
+package indicators
+
+import (
+	"math"
+
+	krakenapi "github.com/beldur/kraken-go-api-client"
+)
+
+// RSI is Wilder's relative strength index of closes, between 0 and 100
+type RSI struct {
+	period   int
+	previous float64
+	count    int
+	gain     float64
+	loss     float64
+}
+
+// NewRSI creates an RSI over period candles, it is ready after period + 1 candles
+func NewRSI(period int) *RSI {
+	return &RSI{period: validPeriod(period)}
+}
+
+// Update adds the close of c
+func (r *RSI) Update(c *krakenapi.OHLC) (float64, bool) {
+	return r.Add(c.Close)
+}
+
+// Add adds a value and returns the index, ok is false until period + 1 values were added
+func (r *RSI) Add(value float64) (float64, bool) {
+	r.count++
+	change := value - r.previous
+	r.previous = value
+	if r.count == 1 {
+		return 0, false
+	}
+
+	gain, loss := math.Max(change, 0), math.Max(-change, 0)
+	n := float64(r.period)
+	if r.count <= r.period+1 {
+		// The first averages are simple averages of period changes
+		r.gain += gain / n
+		r.loss += loss / n
+		if r.count <= r.period {
+			return 0, false
+		}
+	} else {
+		r.gain = (r.gain*(n-1) + gain) / n
+		r.loss = (r.loss*(n-1) + loss) / n
+	}
+
+	switch {
+	case r.gain == 0 && r.loss == 0:
+		return 50, true
+	case r.loss == 0:
+		return 100, true
+	}
+	return 100 - 100/(1+r.gain/r.loss), true
+}
+
+// RSISeries returns the RSI of candles, NaN for the first period candles
+func RSISeries(candles []*krakenapi.OHLC, period int) []float64 {
+	return Series(NewRSI(period), candles)
+}
+
+// MACDValue is a value of the MACD indicator
+type MACDValue struct {
+	// Fast EMA minus slow EMA
+	MACD float64
+	// EMA of MACD
+	Signal float64
+	// MACD minus Signal
+	Histogram float64
+}
+
+// MACD is the moving average convergence divergence of closes
+type MACD struct {
+	fast   *EMA
+	slow   *EMA
+	signal *EMA
+}
+
+// NewMACD creates a MACD, the common parameters are 12, 26 and 9.
+// It is ready after slow + signal - 1 candles.
+func NewMACD(fast, slow, signal int) *MACD {
+	return &MACD{
+		fast:   NewEMA(fast),
+		slow:   NewEMA(slow),
+		signal: NewEMA(signal),
+	}
+}
+
+// Update adds the close of c, ok is false until the signal line is ready
+func (m *MACD) Update(c *krakenapi.OHLC) (MACDValue, bool) {
+	fast, fastOK := m.fast.Add(c.Close)
+	slow, slowOK := m.slow.Add(c.Close)
+	if !fastOK || !slowOK {
+		return MACDValue{MACD: math.NaN(), Signal: math.NaN(), Histogram: math.NaN()}, false
+	}
+
+	value := MACDValue{MACD: fast - slow}
+	signal, ok := m.signal.Add(value.MACD)
+	if !ok {
+		value.Signal, value.Histogram = math.NaN(), math.NaN()
+		return value, false
+	}
+	value.Signal, value.Histogram = signal, value.MACD-signal
+	return value, true
+}
+
+// MACDSeries returns the MACD of candles, values that are not ready yet are NaN
+func MACDSeries(candles []*krakenapi.OHLC, fast, slow, signal int) []MACDValue {
+	m := NewMACD(fast, slow, signal)
+	values := make([]MACDValue, len(candles))
+	for i, c := range candles {
+		values[i], _ = m.Update(c)
+	}
+	return values
+}
+
+// StochasticValue is a value of the stochastic oscillator
+type StochasticValue struct {
+	// Position of the close in the high-low range of the last candles, between 0 and 100
+	K float64
+	// SMA of K
+	D float64
+}
+
+// Stochastic is the stochastic oscillator
+type Stochastic struct {
+	highs *window
+	lows  *window
+	d     *SMA
+}
+
+// NewStochastic creates a stochastic oscillator with K over kPeriod candles and D over dPeriod values of K.
+// It is ready after kPeriod + dPeriod - 1 candles.
+func NewStochastic(kPeriod, dPeriod int) *Stochastic {
+	return &Stochastic{
+		highs: newWindow(kPeriod),
+		lows:  newWindow(kPeriod),
+		d:     NewSMA(dPeriod),
+	}
+}
+
+// Update adds c, ok is false until D is ready.
+// K is 50 when the high-low range is empty.
+func (s *Stochastic) Update(c *krakenapi.OHLC) (StochasticValue, bool) {
+	s.highs.push(c.High)
+	s.lows.push(c.Low)
+	if !s.highs.full {
+		return StochasticValue{K: math.NaN(), D: math.NaN()}, false
+	}
+
+	high, low := math.Inf(-1), math.Inf(1)
+	for i := 0; i < s.highs.len(); i++ {
+		high = math.Max(high, s.highs.at(i))
+		low = math.Min(low, s.lows.at(i))
+	}
+	value := StochasticValue{K: 50}
+	if high > low {
+		value.K = (c.Close - low) / (high - low) * 100
+	}
+
+	d, ok := s.d.Add(value.K)
+	if !ok {
+		value.D = math.NaN()
+		return value, false
+	}
+	value.D = d
+	return value, true
+}
+
+// StochasticSeries returns the stochastic oscillator of candles, values that are not ready yet are NaN
+func StochasticSeries(candles []*krakenapi.OHLC, kPeriod, dPeriod int) []StochasticValue {
+	s := NewStochastic(kPeriod, dPeriod)
+	values := make([]StochasticValue, len(candles))
+	for i, c := range candles {
+		values[i], _ = s.Update(c)
+	}
+	return values
+}
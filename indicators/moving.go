@@ -0,0 +1,114 @@
+package indicators
+
+import (
+	krakenapi "github.com/beldur/kraken-go-api-client"
+)
+
+// SMA is the simple moving average of closes
+type SMA struct {
+	window *window
+	sum    float64
+}
+
+// NewSMA creates an SMA over period candles
+func NewSMA(period int) *SMA {
+	return &SMA{window: newWindow(period)}
+}
+
+// Update adds the close of c
+func (m *SMA) Update(c *krakenapi.OHLC) (float64, bool) {
+	return m.Add(c.Close)
+}
+
+// Add adds a value and returns the average, ok is false until period values were added
+func (m *SMA) Add(value float64) (float64, bool) {
+	old, full := m.window.push(value)
+	if full {
+		m.sum -= old
+	}
+	m.sum += value
+	if !m.window.full {
+		return 0, false
+	}
+	return m.sum / float64(len(m.window.values)), true
+}
+
+// EMA is the exponential moving average of closes, seeded with the SMA of the first period closes
+type EMA struct {
+	period int
+	alpha  float64
+	seed   *SMA
+	value  float64
+	ready  bool
+}
+
+// NewEMA creates an EMA over period candles, with smoothing factor 2 / (period + 1)
+func NewEMA(period int) *EMA {
+	period = validPeriod(period)
+	return &EMA{
+		period: period,
+		alpha:  2 / float64(period+1),
+		seed:   NewSMA(period),
+	}
+}
+
+// Update adds the close of c
+func (m *EMA) Update(c *krakenapi.OHLC) (float64, bool) {
+	return m.Add(c.Close)
+}
+
+// Add adds a value and returns the average, ok is false until period values were added
+func (m *EMA) Add(value float64) (float64, bool) {
+	if !m.ready {
+		m.value, m.ready = m.seed.Add(value)
+		return m.value, m.ready
+	}
+	m.value += m.alpha * (value - m.value)
+	return m.value, true
+}
+
+// WMA is the linearly weighted moving average of closes, the newest close has weight period
+type WMA struct {
+	window *window
+}
+
+// NewWMA creates a WMA over period candles
+func NewWMA(period int) *WMA {
+	return &WMA{window: newWindow(period)}
+}
+
+// Update adds the close of c
+func (m *WMA) Update(c *krakenapi.OHLC) (float64, bool) {
+	return m.Add(c.Close)
+}
+
+// Add adds a value and returns the average, ok is false until period values were added
+func (m *WMA) Add(value float64) (float64, bool) {
+	m.window.push(value)
+	if !m.window.full {
+		return 0, false
+	}
+
+	var sum, weights float64
+	for i := 0; i < m.window.len(); i++ {
+		weight := float64(i + 1)
+		sum += weight * m.window.at(i)
+		weights += weight
+	}
+	return sum / weights, true
+}
+
+// SMASeries returns the SMA of candles, NaN for the first period - 1 candles
+func SMASeries(candles []*krakenapi.OHLC, period int) []float64 {
+	return Series(NewSMA(period), candles)
+}
+
+// EMASeries returns the EMA of candles, NaN for the first period - 1 candles
+func EMASeries(candles []*krakenapi.OHLC, period int) []float64 {
+	return Series(NewEMA(period), candles)
+}
+
+// WMASeries returns the WMA of candles, NaN for the first period - 1 candles
+func WMASeries(candles []*krakenapi.OHLC, period int) []float64 {
+	return Series(NewWMA(period), candles)
+}
@@ -0,0 +1,100 @@
+package indicators
+
+import (
+	"math"
+
+	krakenapi "github.com/beldur/kraken-go-api-client"
+)
+
+// BollingerValue is a value of the Bollinger Bands
+type BollingerValue struct {
+	// SMA of the closes
+	Middle float64
+	// Middle plus and minus the standard deviation times the multiplier
+	Upper float64
+	Lower float64
+}
+
+// Bollinger is the Bollinger Bands of closes
+type Bollinger struct {
+	window     *window
+	multiplier float64
+}
+
+// NewBollinger creates Bollinger Bands over period candles, using the population standard deviation.
+// The common parameters are 20 and 2.
+func NewBollinger(period int, multiplier float64) *Bollinger {
+	return &Bollinger{window: newWindow(period), multiplier: multiplier}
+}
+
+// Update adds the close of c, ok is false until period candles were added
+func (b *Bollinger) Update(c *krakenapi.OHLC) (BollingerValue, bool) {
+	b.window.push(c.Close)
+	if !b.window.full {
+		nan := math.NaN()
+		return BollingerValue{Middle: nan, Upper: nan, Lower: nan}, false
+	}
+
+	n := float64(b.window.len())
+	var sum float64
+	for i := 0; i < b.window.len(); i++ {
+		sum += b.window.at(i)
+	}
+	mean := sum / n
+	var squares float64
+	for i := 0; i < b.window.len(); i++ {
+		d := b.window.at(i) - mean
+		squares += d * d
+	}
+	width := b.multiplier * math.Sqrt(squares/n)
+
+	return BollingerValue{Middle: mean, Upper: mean + width, Lower: mean - width}, true
+}
+
+// BollingerSeries returns the Bollinger Bands of candles, values that are not ready yet are NaN
+func BollingerSeries(candles []*krakenapi.OHLC, period int, multiplier float64) []BollingerValue {
+	b := NewBollinger(period, multiplier)
+	values := make([]BollingerValue, len(candles))
+	for i, c := range candles {
+		values[i], _ = b.Update(c)
+	}
+	return values
+}
+
+// ATR is Wilder's average true range
+type ATR struct {
+	period   int
+	previous float64
+	count    int
+	value    float64
+}
+
+// NewATR creates an ATR over period candles
+func NewATR(period int) *ATR {
+	return &ATR{period: validPeriod(period)}
+}
+
+// Update adds c and returns the average true range, ok is false until period candles were added.
+// The true range of the first candle is its high-low range.
+func (a *ATR) Update(c *krakenapi.OHLC) (float64, bool) {
+	tr := c.High - c.Low
+	if a.count > 0 {
+		tr = math.Max(tr, math.Max(math.Abs(c.High-a.previous), math.Abs(c.Low-a.previous)))
+	}
+	a.previous = c.Close
+	a.count++
+
+	n := float64(a.period)
+	if a.count <= a.period {
+		// The first average is the simple average of period true ranges
+		a.value += tr / n
+		return a.value, a.count == a.period
+	}
+	a.value = (a.value*(n-1) + tr) / n
+	return a.value, true
+}
+
+// ATRSeries returns the ATR of candles, NaN for the first period - 1 candles
+func ATRSeries(candles []*krakenapi.OHLC, period int) []float64 {
+	return Series(NewATR(period), candles)
+}
@@ -0,0 +1,94 @@
+package indicators
+
+import (
+	krakenapi "github.com/beldur/kraken-go-api-client"
+)
+
+// OBV is the on-balance volume, starting at 0 with the first candle
+type OBV struct {
+	previous float64
+	started  bool
+	value    float64
+}
+
+// NewOBV creates an OBV
+func NewOBV() *OBV {
+	return &OBV{}
+}
+
+// Update adds c and returns the on-balance volume, it is always ready
+func (o *OBV) Update(c *krakenapi.OHLC) (float64, bool) {
+	if o.started {
+		switch {
+		case c.Close > o.previous:
+			o.value += c.Volume
+		case c.Close < o.previous:
+			o.value -= c.Volume
+		}
+	}
+	o.previous, o.started = c.Close, true
+	return o.value, true
+}
+
+// OBVSeries returns the OBV of candles
+func OBVSeries(candles []*krakenapi.OHLC) []float64 {
+	return Series(NewOBV(), candles)
+}
+
+// VWAP is the volume weighted average price of candles.
+// Each candle is weighted at its own VWAP as reported by Kraken, or at its typical price
+// (high + low + close) / 3 if it has none.
+type VWAP struct {
+	period  int
+	costs   *window
+	volumes *window
+	cost    float64
+	volume  float64
+}
+
+// NewVWAP creates a VWAP over the last period candles, or over every candle since the last Reset if period is 0
+func NewVWAP(period int) *VWAP {
+	v := &VWAP{period: period}
+	if period > 0 {
+		v.costs, v.volumes = newWindow(period), newWindow(period)
+	}
+	return v
+}
+
+// Reset starts a new session of a cumulative VWAP
+func (v *VWAP) Reset() {
+	*v = *NewVWAP(v.period)
+}
+
+// Update adds c, ok is false until period candles were added or while there was no volume
+func (v *VWAP) Update(c *krakenapi.OHLC) (float64, bool) {
+	price := c.Vwap
+	if price == 0 {
+		price = (c.High + c.Low + c.Close) / 3
+	}
+	cost := price * c.Volume
+
+	v.cost += cost
+	v.volume += c.Volume
+	if v.period > 0 {
+		if old, full := v.costs.push(cost); full {
+			v.cost -= old
+		}
+		if old, full := v.volumes.push(c.Volume); full {
+			v.volume -= old
+		}
+		if !v.volumes.full {
+			return 0, false
+		}
+	}
+
+	if v.volume <= 0 {
+		return 0, false
+	}
+	return v.cost / v.volume, true
+}
+
+// VWAPSeries returns the VWAP of candles over period candles, or cumulated over all candles if period is 0
+func VWAPSeries(candles []*krakenapi.OHLC, period int) []float64 {
+	return Series(NewVWAP(period), candles)
+}
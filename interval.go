@@ -0,0 +1,89 @@
+package krakenapi
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Interval is an OHLC candle interval in minutes
+type Interval int
+
+// Intervals supported by Kraken, see https://docs.kraken.com/rest/#operation/getOHLCData
+const (
+	Interval1m  Interval = 1
+	Interval5m  Interval = 5
+	Interval15m Interval = 15
+	Interval30m Interval = 30
+	Interval1h  Interval = 60
+	Interval4h  Interval = 240
+	Interval1d  Interval = 1440
+	Interval1w  Interval = 10080
+	Interval15d Interval = 21600
+)
+
+// Intervals lists every supported interval in ascending order
+var Intervals = []Interval{
+	Interval1m,
+	Interval5m,
+	Interval15m,
+	Interval30m,
+	Interval1h,
+	Interval4h,
+	Interval1d,
+	Interval1w,
+	Interval15d,
+}
+
+// ParseInterval parses an interval given in minutes, like the Kraken API does
+func ParseInterval(minutes string) (Interval, error) {
+	i, err := strconv.Atoi(minutes)
+	if err != nil || !Interval(i).Valid() {
+		return 0, fmt.Errorf("Unsupported value for Interval: %s", minutes)
+	}
+	return Interval(i), nil
+}
+
+// IntervalFromDuration returns the interval of given duration
+func IntervalFromDuration(d time.Duration) (Interval, error) {
+	if d%time.Minute == 0 {
+		if i := Interval(d / time.Minute); i.Valid() {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("Unsupported duration for Interval: %s", d)
+}
+
+// Valid returns true if Kraken supports the interval
+func (i Interval) Valid() bool {
+	for _, interval := range Intervals {
+		if i == interval {
+			return true
+		}
+	}
+	return false
+}
+
+// Duration returns the length of the interval
+func (i Interval) Duration() time.Duration {
+	return time.Duration(i) * time.Minute
+}
+
+// String returns the interval in minutes as used by the Kraken API
+func (i Interval) String() string {
+	return strconv.Itoa(int(i))
+}
+
+// Align returns the start of the candle containing t. Candles are aligned to the unix epoch.
+func (i Interval) Align(t time.Time) time.Time {
+	seconds := int64(i.Duration() / time.Second)
+	if seconds <= 0 {
+		return t
+	}
+	unix := t.Unix()
+	start := unix - unix%seconds
+	if unix%seconds < 0 {
+		start -= seconds
+	}
+	return time.Unix(start, 0).In(t.Location())
+}
@@ -0,0 +1,37 @@
+package krakenapi
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInterval(t *testing.T) {
+	for _, interval := range Intervals {
+		parsed, err := ParseInterval(interval.String())
+		if err != nil || parsed != interval {
+			t.Errorf("ParseInterval(%s) should return %d, got %d %v", interval, interval, parsed, err)
+		}
+		fromDuration, err := IntervalFromDuration(interval.Duration())
+		if err != nil || fromDuration != interval {
+			t.Errorf("IntervalFromDuration(%s) should return %d, got %d %v", interval.Duration(), interval, fromDuration, err)
+		}
+	}
+
+	if Interval15d.Duration() != 15*24*time.Hour {
+		t.Errorf("Interval15d should last 15 days, got %s", Interval15d.Duration())
+	}
+	if _, err := ParseInterval("2"); err == nil {
+		t.Errorf("ParseInterval(2) should fail")
+	}
+	if _, err := IntervalFromDuration(90 * time.Second); err == nil {
+		t.Errorf("IntervalFromDuration(90s) should fail")
+	}
+
+	ts := time.Date(2023, 7, 6, 18, 52, 31, 0, time.UTC)
+	if aligned := Interval4h.Align(ts); !aligned.Equal(time.Date(2023, 7, 6, 16, 0, 0, 0, time.UTC)) {
+		t.Errorf("Align() should return the start of the 4h candle, got %s", aligned)
+	}
+	if aligned := Interval15m.Align(ts); !aligned.Equal(time.Date(2023, 7, 6, 18, 45, 0, 0, time.UTC)) {
+		t.Errorf("Align() should return the start of the 15m candle, got %s", aligned)
+	}
+}
@@ -28,19 +28,28 @@ func New(key, secret string) API {
 	return NewWithClient(key, secret, http.DefaultClient)
 }
 
-// NewWithClient creates a new Kraken API client with custom http client
+// NewWithClient creates a new Kraken API client with custom http client.
+// Its requests are not rate limited, except DepthMulti which paces itself, use NewWithLimiter to pace them all.
 func NewWithClient(key, secret string, httpClient *http.Client) API {
+	return NewWithLimiter(key, secret, httpClient, nil)
+}
+
+// NewWithLimiter creates a new Kraken API client with custom http client whose requests
+// are paced by limiter. Public and private requests share the limiter, nil disables it.
+func NewWithLimiter(key, secret string, httpClient *http.Client, limiter *RateLimiter) API {
 	api := &krakenAPI{
 		public: &KrakenPublic{
 			KrakenClient{
-				client: httpClient,
+				client:  httpClient,
+				limiter: limiter,
 			},
 		},
 		private: &KrakenPrivate{
 			key:    key,
 			secret: secret,
 			KrakenClient: KrakenClient{
-				client: httpClient,
+				client:  httpClient,
+				limiter: limiter,
 			},
 		},
 	}
@@ -1,7 +1,12 @@
 package krakenapi
 
 import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -25,6 +30,36 @@ func TestAssets(t *testing.T) {
 	}
 }
 
+func TestAssetsWithClass(t *testing.T) {
+	api := newTestAPI(func(method string, values url.Values) string {
+		if values.Get("asset") != "XBT,ETH,DOT" || values.Get("aclass") != "currency" {
+			t.Errorf("AssetsWithClass() should send asset and aclass, got %v", values)
+		}
+		return `{"error":[],"result":{` +
+			`"XXBT":{"aclass":"currency","altname":"XBT","decimals":10,"display_decimals":5,"collateral_value":1,"status":"enabled"},` +
+			`"XETH":{"aclass":"currency","altname":"ETH","decimals":10,"display_decimals":5,"collateral_value":1,"status":"deposit_only"},` +
+			`"DOT":{"aclass":"currency","altname":"DOT","decimals":10,"display_decimals":8,"margin_rate":0.02,"status":"funding_temporarily_disabled"}}}`
+	})
+
+	resp, err := api.Public().AssetsWithClass("currency", "XBT", "ETH", "DOT")
+	if err != nil {
+		t.Fatalf("AssetsWithClass() should not return an error, got %s", err)
+	}
+
+	if asset, info, ok := resp.GetAssetByAltname("XBT"); !ok || asset != "XXBT" || info.CollateralValue != 1 {
+		t.Errorf("GetAssetByAltname() should return XXBT, got %s %+v", asset, info)
+	}
+	if resp.GetAsset("DOT").MarginRate != 0.02 {
+		t.Errorf("AssetsWithClass() should return margin_rate, got %+v", resp.GetAsset("DOT"))
+	}
+	if assets := resp.GetEnabledAssets(); !reflect.DeepEqual(assets, []string{"XXBT"}) {
+		t.Errorf("GetEnabledAssets() should return enabled assets only, got %v", assets)
+	}
+	if assets := resp.GetDepositAssets(); !reflect.DeepEqual(assets, []string{"XETH", "XXBT"}) {
+		t.Errorf("GetDepositAssets() should return deposit-capable assets, got %v", assets)
+	}
+}
+
 func TestAssetPairs(t *testing.T) {
 	resp, err := api.Public().AssetPairs()
 	if err != nil {
@@ -40,6 +75,31 @@ func TestAssetPairs(t *testing.T) {
 	}
 }
 
+func TestAssetPairsWithInfo(t *testing.T) {
+	api := newTestAPI(func(method string, values url.Values) string {
+		if values.Get("pair") != "XBTUSD,ETHUSD" || values.Get("info") != "margin" {
+			t.Errorf("AssetPairsWithInfo() should send pair and info, got %v", values)
+		}
+		return `{"error":[],"result":{"XXBTZUSD":{"altname":"XBTUSD","wsname":"XBT/USD","base":"XXBT","quote":"ZUSD",` +
+			`"cost_decimals":5,"ordermin":"0.0001","costmin":"0.5","tick_size":"0.1","status":"online",` +
+			`"margin_call":80,"margin_stop":40.5,"long_position_limit":270,"short_position_limit":180}}}`
+	})
+
+	resp, err := api.Public().AssetPairsWithInfo(PairInfoMargin, "XBTUSD", "ETHUSD")
+	if err != nil {
+		t.Fatalf("AssetPairsWithInfo() should not return an error, got %s", err)
+	}
+
+	expected := AssetPairInfo{
+		Altname: "XBTUSD", WSName: "XBT/USD", Base: "XXBT", Quote: "ZUSD",
+		CostDecimals: 5, OrderMin: 0.0001, CostMin: 0.5, TickSize: 0.1, Status: "online",
+		MarginCall: 80, MarginStop: 40.5, LongPositionLimit: 270, ShortPositionLimit: 180,
+	}
+	if info := resp.GetAssetPair(XXBTZUSD); !reflect.DeepEqual(info, expected) {
+		t.Errorf("AssetPairsWithInfo() should return %+v, got %+v", expected, info)
+	}
+}
+
 func TestTicker(t *testing.T) {
 	resp, err := api.Public().Ticker(XXBTZEUR, XXRPZEUR)
 	if err != nil {
@@ -52,7 +112,7 @@ func TestTicker(t *testing.T) {
 }
 
 func TestOHLC(t *testing.T) {
-	resp, err := api.Public().OHLC(XXBTZEUR)
+	resp, err := api.Public().OHLC(XXBTZEUR, Interval1m, 0)
 	if err != nil {
 		t.Errorf("OHLC() should not return an error, got %s", err)
 	}
@@ -107,3 +167,32 @@ func TestQueryDepth(t *testing.T) {
 		t.Errorf("Bids length must be less than count , got %d > %d", len(result.Bids), count)
 	}
 }
+
+// roundTripFunc answers HTTP requests in-process so tests don't need the network
+type roundTripFunc func(req *http.Request) *http.Response
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req), nil
+}
+
+// newTestAPI creates a client whose requests are answered by handler, which gets the
+// Kraken method name and form values and returns the JSON response body.
+func newTestAPI(handler func(method string, values url.Values) string) API {
+	return NewWithClient("", "", newTestClient(handler))
+}
+
+// newTestClient creates a http client whose requests are answered by handler, see newTestAPI
+func newTestClient(handler func(method string, values url.Values) string) *http.Client {
+	return &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) *http.Response {
+			form, _ := ioutil.ReadAll(req.Body)
+			values, _ := url.ParseQuery(string(form))
+			body := handler(path.Base(req.URL.Path), values)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": {"application/json"}},
+				Body:       ioutil.NopCloser(strings.NewReader(body)),
+			}
+		}),
+	}
+}
@@ -1,6 +1,7 @@
 package krakenapi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -17,17 +18,32 @@ const (
 
 // krakenAPI represents a Kraken API Client connection
 type KrakenClient struct {
-	client *http.Client
+	client  *http.Client
+	limiter *RateLimiter
 }
 
 // doRequest executes a HTTP Request to the Kraken API and returns the result
 func (api *KrakenClient) doRequest(reqURL string, values url.Values, headers map[string]string, typ interface{}) (interface{}, error) {
+	return api.doRequestContext(context.Background(), reqURL, values, headers, typ)
+}
+
+// doRequestContext executes a HTTP Request to the Kraken API, once the rate limiter allows it, and returns the result
+func (api *KrakenClient) doRequestContext(ctx context.Context, reqURL string, values url.Values, headers map[string]string, typ interface{}) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if api.limiter != nil {
+		if err := api.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
 
 	// Create request
 	req, err := http.NewRequest("POST", reqURL, strings.NewReader(values.Encode()))
 	if err != nil {
 		return nil, fmt.Errorf("Could not execute request! #1 (%s)", err.Error())
 	}
+	req = req.WithContext(ctx)
 
 	req.Header.Add("User-Agent", APIUserAgent)
 	for key, value := range headers {
@@ -0,0 +1,135 @@
+package krakenapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// OHLCIterator walks forward through the OHLC candles of a pair from a start time, following
+// the `last` cursor. It removes candles repeated at page boundaries and ends at the end time
+// or once it caught up with the still forming candle, which is dropped unless IncludeUnfinished is set.
+//
+//	it := NewOHLCIterator(api.Public(), XXBTZEUR, Interval1h, start, end)
+//	for it.Next(ctx) {
+//		candle := it.OHLC()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// Kraken only serves the most recent 720 candles of each interval, older candles are not returned.
+type OHLCIterator struct {
+	// Also yield the still forming last candle, see Unfinished
+	IncludeUnfinished bool
+
+	api        PublicAPI
+	pair       string
+	interval   Interval
+	start      time.Time
+	end        time.Time
+	since      int64
+	emitted    time.Time
+	buffer     []*OHLC
+	current    *OHLC
+	unfinished bool
+	done       bool
+	err        error
+	now        func() time.Time
+}
+
+// NewOHLCIterator creates an OHLCIterator for the candles of pair in [start, end), end may be zero to walk up to now
+func NewOHLCIterator(api PublicAPI, pair string, interval Interval, start, end time.Time) *OHLCIterator {
+	it := &OHLCIterator{
+		api:      api,
+		pair:     pair,
+		interval: interval,
+		start:    start,
+		end:      end,
+		since:    start.Unix() - 1,
+		now:      time.Now,
+	}
+
+	if !interval.Valid() {
+		it.err = fmt.Errorf("Unsupported value for Interval: %d", interval)
+	}
+
+	return it
+}
+
+// Next advances to the next candle. It returns false when the iterator reached the end
+// or a request failed, see Err.
+func (it *OHLCIterator) Next(ctx context.Context) bool {
+	for len(it.buffer) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		if it.err = ctx.Err(); it.err != nil {
+			return false
+		}
+		it.fetch()
+	}
+
+	it.current, it.buffer = it.buffer[0], it.buffer[1:]
+	it.emitted = it.current.Time
+	it.unfinished = it.isUnfinished(it.current)
+	return true
+}
+
+// fetch loads the next page of candles into the buffer
+func (it *OHLCIterator) fetch() {
+	resp, err := it.api.OHLC(it.pair, it.interval, it.since)
+	if err != nil {
+		it.err = err
+		return
+	}
+
+	for _, candle := range resp.OHLC {
+		if candle.Time.Before(it.start) || !candle.Time.After(it.emitted) {
+			continue
+		}
+		if !it.end.IsZero() && !candle.Time.Before(it.end) {
+			it.done = true
+			break
+		}
+		if it.isUnfinished(candle) {
+			it.done = true
+			if it.IncludeUnfinished {
+				it.buffer = append(it.buffer, candle)
+			}
+			break
+		}
+		it.buffer = append(it.buffer, candle)
+	}
+
+	// A page without new candles means we caught up
+	if len(it.buffer) == 0 || resp.Last <= it.since {
+		it.done = true
+	}
+	it.since = resp.Last
+}
+
+// isUnfinished returns true if the candle's interval hasn't ended yet
+func (it *OHLCIterator) isUnfinished(candle *OHLC) bool {
+	return candle.Time.Add(it.interval.Duration()).After(it.now())
+}
+
+// OHLC returns the current candle
+func (it *OHLCIterator) OHLC() *OHLC {
+	return it.current
+}
+
+// Unfinished returns true if the current candle is still forming
+func (it *OHLCIterator) Unfinished() bool {
+	return it.unfinished
+}
+
+// Last returns the `last` cursor of the most recent page
+func (it *OHLCIterator) Last() int64 {
+	return it.since
+}
+
+// Err returns the error that stopped the iterator
+func (it *OHLCIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,68 @@
+package krakenapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ohlcPage returns an OHLC response holding hourly candles starting at the unix times from to to
+func ohlcPage(pair string, from, to, last int64) string {
+	var candles []string
+	for ts := from; ts <= to; ts += 3600 {
+		candles = append(candles, fmt.Sprintf(`[%d,"1.0","2.0","0.5","1.5","1.2","10.0",5]`, ts))
+	}
+	return fmt.Sprintf(`{"error":[],"result":{"%s":[%s],"last":%d}}`, pair, strings.Join(candles, ","), last)
+}
+
+func TestOHLCIterator(t *testing.T) {
+	const hour = 3600
+	start := int64(1688601600)
+
+	api := newTestAPI(func(method string, values url.Values) string {
+		since, _ := strconv.ParseInt(values.Get("since"), 10, 64)
+		switch since {
+		case start - 1:
+			return ohlcPage(XXBTZEUR, start, start+3*hour, start+3*hour)
+		case start + 3*hour:
+			// repeats the last candle of the previous page and ends with the forming candle
+			return ohlcPage(XXBTZEUR, start+3*hour, start+6*hour, start+5*hour)
+		}
+		return ohlcPage(XXBTZEUR, start+6*hour, start+6*hour, start+5*hour)
+	})
+
+	collect := func(it *OHLCIterator) []int64 {
+		it.now = func() time.Time { return time.Unix(start+6*hour+60, 0) }
+		var times []int64
+		for it.Next(context.Background()) {
+			times = append(times, it.OHLC().Time.Unix())
+			if it.Unfinished() != (it.OHLC().Time.Unix() == start+6*hour) {
+				t.Errorf("Unfinished() should only be true for the forming candle, got %v for %d", it.Unfinished(), it.OHLC().Time.Unix())
+			}
+		}
+		if it.Err() != nil {
+			t.Errorf("OHLCIterator should not return an error, got %s", it.Err())
+		}
+		return times
+	}
+
+	times := collect(NewOHLCIterator(api.Public(), XXBTZEUR, Interval1h, time.Unix(start, 0), time.Time{}))
+	if len(times) != 6 || times[0] != start || times[5] != start+5*hour {
+		t.Errorf("OHLCIterator should yield every finished candle once, got %v", times)
+	}
+
+	it := NewOHLCIterator(api.Public(), XXBTZEUR, Interval1h, time.Unix(start, 0), time.Time{})
+	it.IncludeUnfinished = true
+	if times := collect(it); len(times) != 7 {
+		t.Errorf("OHLCIterator should yield the forming candle when asked, got %v", times)
+	}
+
+	times = collect(NewOHLCIterator(api.Public(), XXBTZEUR, Interval1h, time.Unix(start, 0), time.Unix(start+2*hour, 0)))
+	if len(times) != 2 || times[1] != start+hour {
+		t.Errorf("OHLCIterator should stop at the end time, got %v", times)
+	}
+}
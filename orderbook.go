@@ -0,0 +1,172 @@
+package krakenapi
+
+import (
+	"errors"
+	"fmt"
+)
+
+// BookSide selects the asks or the bids of an OrderBook
+type BookSide int
+
+// BookSide values, buying takes the asks and selling takes the bids
+const (
+	AskSide BookSide = iota
+	BidSide
+)
+
+func (s BookSide) String() string {
+	if s == AskSide {
+		return "asks"
+	}
+	return "bids"
+}
+
+// ErrEmptyBook is returned by OrderBook analytics when the requested side has no orders
+var ErrEmptyBook = errors.New("order book side is empty")
+
+// The analytics below rely on Kraken returning asks ordered by ascending and bids by descending price.
+
+// levels returns the orders of given side
+func (b *OrderBook) levels(side BookSide) []OrderBookItem {
+	if side == AskSide {
+		return b.Asks
+	}
+	return b.Bids
+}
+
+// BestAsk returns the lowest ask
+func (b *OrderBook) BestAsk() (OrderBookItem, bool) {
+	if len(b.Asks) == 0 {
+		return OrderBookItem{}, false
+	}
+	return b.Asks[0], true
+}
+
+// BestBid returns the highest bid
+func (b *OrderBook) BestBid() (OrderBookItem, bool) {
+	if len(b.Bids) == 0 {
+		return OrderBookItem{}, false
+	}
+	return b.Bids[0], true
+}
+
+// Mid returns the price between best ask and best bid, 0 if a side is empty
+func (b *OrderBook) Mid() float64 {
+	ask, okAsk := b.BestAsk()
+	bid, okBid := b.BestBid()
+	if !okAsk || !okBid {
+		return 0
+	}
+	return (ask.Price + bid.Price) / 2
+}
+
+// Spread returns the difference between best ask and best bid, 0 if a side is empty
+func (b *OrderBook) Spread() float64 {
+	ask, okAsk := b.BestAsk()
+	bid, okBid := b.BestBid()
+	if !okAsk || !okBid {
+		return 0
+	}
+	return ask.Price - bid.Price
+}
+
+// DepthTo returns the cumulative amount of side up to and including price
+func (b *OrderBook) DepthTo(side BookSide, price float64) float64 {
+	var amount float64
+	for _, level := range b.levels(side) {
+		if !withinPrice(side, level.Price, price) {
+			break
+		}
+		amount += level.Amount
+	}
+	return amount
+}
+
+// VWAPTo returns the volume weighted average price of side up to and including price
+func (b *OrderBook) VWAPTo(side BookSide, price float64) (float64, error) {
+	var amount, cost float64
+	for _, level := range b.levels(side) {
+		if !withinPrice(side, level.Price, price) {
+			break
+		}
+		amount += level.Amount
+		cost += level.Amount * level.Price
+	}
+	if amount == 0 {
+		return 0, fmt.Errorf("no %s up to price %v", side, price)
+	}
+	return cost / amount, nil
+}
+
+// FillPrice returns the average price a market order of size would get when filled against side,
+// which is the volume weighted average price of the levels it takes.
+func (b *OrderBook) FillPrice(side BookSide, size float64) (float64, error) {
+	if size <= 0 {
+		return 0, fmt.Errorf("size must be positive, got %v", size)
+	}
+
+	remaining, cost := size, 0.0
+	for _, level := range b.levels(side) {
+		take := level.Amount
+		if take > remaining {
+			take = remaining
+		}
+		cost += take * level.Price
+		remaining -= take
+		if remaining <= 0 {
+			return cost / size, nil
+		}
+	}
+
+	if remaining == size {
+		return 0, ErrEmptyBook
+	}
+	return 0, fmt.Errorf("%s only hold %v of size %v", side, size-remaining, size)
+}
+
+// SlippageBps returns how much worse than the best price the fill price of size is, in basis points
+func (b *OrderBook) SlippageBps(side BookSide, size float64) (float64, error) {
+	levels := b.levels(side)
+	if len(levels) == 0 {
+		return 0, ErrEmptyBook
+	}
+	fill, err := b.FillPrice(side, size)
+	if err != nil {
+		return 0, err
+	}
+
+	best := levels[0].Price
+	if side == AskSide {
+		return (fill - best) / best * 10000, nil
+	}
+	return (best - fill) / best * 10000, nil
+}
+
+// Imbalance returns (bid amount - ask amount) / (bid amount + ask amount) of the top levels of
+// each side, all levels if levels is 0. It ranges from -1 (only asks) to 1 (only bids).
+func (b *OrderBook) Imbalance(levels int) float64 {
+	sum := func(items []OrderBookItem) float64 {
+		var amount float64
+		for i, item := range items {
+			if levels > 0 && i >= levels {
+				break
+			}
+			amount += item.Amount
+		}
+		return amount
+	}
+
+	bids, asks := sum(b.Bids), sum(b.Asks)
+	if bids+asks == 0 {
+		return 0
+	}
+	return (bids - asks) / (bids + asks)
+}
+
+// withinPrice returns true if a level at levelPrice of side is at or better than price
+func withinPrice(side BookSide, levelPrice, price float64) bool {
+	if side == AskSide {
+		return levelPrice <= price
+	}
+	return levelPrice >= price
+}
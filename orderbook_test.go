@@ -0,0 +1,61 @@
+package krakenapi
+
+import (
+	"math"
+	"testing"
+)
+
+var testBook = OrderBook{
+	Asks: []OrderBookItem{{Price: 101, Amount: 1}, {Price: 102, Amount: 2}, {Price: 104, Amount: 3}},
+	Bids: []OrderBookItem{{Price: 99, Amount: 2}, {Price: 98, Amount: 2}, {Price: 95, Amount: 4}},
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestOrderBookAnalytics(t *testing.T) {
+	if ask, _ := testBook.BestAsk(); ask.Price != 101 {
+		t.Errorf("BestAsk() should return 101, got %v", ask.Price)
+	}
+	if bid, _ := testBook.BestBid(); bid.Price != 99 {
+		t.Errorf("BestBid() should return 99, got %v", bid.Price)
+	}
+	if testBook.Mid() != 100 || testBook.Spread() != 2 {
+		t.Errorf("Mid() and Spread() should return 100 and 2, got %v %v", testBook.Mid(), testBook.Spread())
+	}
+
+	if depth := testBook.DepthTo(AskSide, 102); depth != 3 {
+		t.Errorf("DepthTo(asks, 102) should return 3, got %v", depth)
+	}
+	if depth := testBook.DepthTo(BidSide, 98); depth != 4 {
+		t.Errorf("DepthTo(bids, 98) should return 4, got %v", depth)
+	}
+	if vwap, _ := testBook.VWAPTo(AskSide, 102); !almostEqual(vwap, 305.0/3) {
+		t.Errorf("VWAPTo(asks, 102) should return 101.67, got %v", vwap)
+	}
+
+	if price, _ := testBook.FillPrice(AskSide, 2); price != 101.5 {
+		t.Errorf("FillPrice(asks, 2) should return 101.5, got %v", price)
+	}
+	if price, _ := testBook.FillPrice(BidSide, 5); !almostEqual(price, (2*99+2*98+95)/5.0) {
+		t.Errorf("FillPrice(bids, 5) should return 97.8, got %v", price)
+	}
+	if _, err := testBook.FillPrice(AskSide, 10); err == nil {
+		t.Errorf("FillPrice() should fail when the book is too thin")
+	}
+
+	if slippage, _ := testBook.SlippageBps(AskSide, 2); !almostEqual(slippage, 0.5/101*10000) {
+		t.Errorf("SlippageBps(asks, 2) should return 49.5, got %v", slippage)
+	}
+	if slippage, _ := testBook.SlippageBps(BidSide, 2); slippage != 0 {
+		t.Errorf("SlippageBps(bids, 2) should return 0, got %v", slippage)
+	}
+
+	if imbalance := testBook.Imbalance(1); !almostEqual(imbalance, 1.0/3) {
+		t.Errorf("Imbalance(1) should return 0.33, got %v", imbalance)
+	}
+	if imbalance := testBook.Imbalance(0); !almostEqual(imbalance, 2.0/14) {
+		t.Errorf("Imbalance(0) should return 0.14, got %v", imbalance)
+	}
+}
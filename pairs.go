@@ -0,0 +1,467 @@
+package krakenapi
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// assetAliases maps Kraken asset altnames to their common tickers
+var assetAliases = map[string]string{
+	"XBT": "BTC",
+	"XDG": "DOGE",
+}
+
+// PairResolver maps the names of asset pairs between their forms: canonical (XXBTZUSD),
+// altname (XBTUSD), websocket name (XBT/USD) and human name (BTC/USD). Lookups are case insensitive.
+type PairResolver struct {
+	pairs     map[string]AssetPairInfo
+	canonical map[string]string
+}
+
+// NewPairResolver creates a PairResolver for the pairs of an AssetPairs response
+func NewPairResolver(pairs AssetPairsResponse) *PairResolver {
+	r := &PairResolver{
+		pairs:     make(map[string]AssetPairInfo),
+		canonical: make(map[string]string),
+	}
+
+	names := pairs.GetPairs()
+	sort.Strings(names)
+
+	// Register every exact form first so that a derived form can never shadow another pair's name
+	for _, name := range names {
+		info := pairs.GetAssetPair(name)
+		r.pairs[name] = info
+		for _, form := range []string{name, info.Altname, info.WSName} {
+			r.register(form, name)
+		}
+	}
+	for _, name := range names {
+		human := r.human(r.pairs[name])
+		r.register(human, name)
+		r.register(strings.Replace(human, "/", "", 1), name)
+	}
+
+	return r
+}
+
+// LoadPairResolver creates a PairResolver for all asset pairs of api
+func LoadPairResolver(api PublicAPI) (*PairResolver, error) {
+	pairs, err := api.AssetPairs()
+	if err != nil {
+		return nil, err
+	}
+	return NewPairResolver(pairs), nil
+}
+
+func (r *PairResolver) register(form, name string) {
+	if form == "" {
+		return
+	}
+	key := strings.ToUpper(form)
+	if _, ok := r.canonical[key]; !ok {
+		r.canonical[key] = name
+	}
+}
+
+// Resolve returns the canonical name of pair given in any form
+func (r *PairResolver) Resolve(pair string) (string, error) {
+	if name, ok := r.canonical[strings.ToUpper(pair)]; ok {
+		return name, nil
+	}
+	return "", fmt.Errorf("unknown asset pair %s", pair)
+}
+
+// resolveOrKeep returns the canonical name of pair, or pair itself if it is unknown
+func (r *PairResolver) resolveOrKeep(pair string) string {
+	if name, err := r.Resolve(pair); err == nil {
+		return name
+	}
+	return pair
+}
+
+// Info returns the AssetPairInfo of pair given in any form
+func (r *PairResolver) Info(pair string) (AssetPairInfo, error) {
+	name, err := r.Resolve(pair)
+	if err != nil {
+		return AssetPairInfo{}, err
+	}
+	return r.pairs[name], nil
+}
+
+// Altname returns the alternate name of pair given in any form
+func (r *PairResolver) Altname(pair string) (string, error) {
+	info, err := r.Info(pair)
+	return info.Altname, err
+}
+
+// WSName returns the websocket name of pair given in any form
+func (r *PairResolver) WSName(pair string) (string, error) {
+	info, err := r.Info(pair)
+	return info.WSName, err
+}
+
+// Human returns the name of pair given in any form using common tickers, e.g. BTC/USD
+func (r *PairResolver) Human(pair string) (string, error) {
+	info, err := r.Info(pair)
+	if err != nil {
+		return "", err
+	}
+	return r.human(info), nil
+}
+
+func (r *PairResolver) human(info AssetPairInfo) string {
+	parts := strings.Split(info.WSName, "/")
+	if len(parts) != 2 {
+		return ""
+	}
+	for i, part := range parts {
+		if alias, ok := assetAliases[part]; ok {
+			parts[i] = alias
+		}
+	}
+	return parts[0] + "/" + parts[1]
+}
+
+// Pairs returns the canonical names of all pairs
+func (r *PairResolver) Pairs() []string {
+	names := make([]string, 0, len(r.pairs))
+	for name := range r.pairs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WithPairResolver wraps api so that every method taking a pair accepts it in any form known to r.
+// The pairs of Ticker, AssetPairs and TradeVolume responses can be looked up in any form, MarketSnapshot
+// and DepthMulti are keyed by the pair names as given, and trades and order descriptions name their pair canonically.
+// Unknown pairs are passed to Kraken unchanged.
+func WithPairResolver(api API, r *PairResolver) API {
+	return &resolvingAPI{
+		public:  &resolvingPublic{public: api.Public(), resolver: r},
+		private: &resolvingPrivate{private: api.Private(), resolver: r},
+	}
+}
+
+type resolvingAPI struct {
+	public  PublicAPI
+	private PrivateAPI
+}
+
+func (api *resolvingAPI) Public() PublicAPI {
+	return api.public
+}
+
+func (api *resolvingAPI) Private() PrivateAPI {
+	return api.private
+}
+
+// resolvingPublic resolves the pairs of the wrapped PublicAPI's calls. It implements every PublicAPI
+// method explicitly instead of embedding the wrapped API, so a method added to PublicAPI can't skip resolution.
+type resolvingPublic struct {
+	public   PublicAPI
+	resolver *PairResolver
+}
+
+func (api *resolvingPublic) resolveAll(pairs []string) []string {
+	resolved := make([]string, len(pairs))
+	for i, pair := range pairs {
+		resolved[i] = api.resolver.resolveOrKeep(pair)
+	}
+	return resolved
+}
+
+func (api *resolvingPublic) Time() (*TimeResponse, error) {
+	return api.public.Time()
+}
+
+func (api *resolvingPublic) Assets(assets ...string) (AssetsResponse, error) {
+	return api.public.Assets(assets...)
+}
+
+func (api *resolvingPublic) AssetsWithClass(aclass string, assets ...string) (AssetsResponse, error) {
+	return api.public.AssetsWithClass(aclass, assets...)
+}
+
+func (api *resolvingPublic) AssetPairs(pairs ...string) (AssetPairsResponse, error) {
+	return api.AssetPairsWithInfo("", pairs...)
+}
+
+func (api *resolvingPublic) AssetPairsWithInfo(info PairInfoMode, pairs ...string) (AssetPairsResponse, error) {
+	resp, err := api.public.AssetPairsWithInfo(info, api.resolveAll(pairs)...)
+	if err != nil {
+		return nil, err
+	}
+	return &resolvedAssetPairs{AssetPairsResponse: resp, resolver: api.resolver}, nil
+}
+
+func (api *resolvingPublic) Ticker(pairs ...string) (TickerResponse, error) {
+	resp, err := api.public.Ticker(api.resolveAll(pairs)...)
+	if err != nil {
+		return nil, err
+	}
+	return &resolvedTickers{TickerResponse: resp, resolver: api.resolver}, nil
+}
+
+// MarketSnapshot returns the tickers and errors keyed by the pair names as given,
+// or by their canonical names if no pairs are given
+func (api *resolvingPublic) MarketSnapshot(pairs ...string) (*MarketSnapshot, error) {
+	resolved := api.resolveAll(pairs)
+	snapshot, err := api.public.MarketSnapshot(resolved...)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string, len(pairs))
+	for i, pair := range pairs {
+		names[resolved[i]] = pair
+	}
+	given := func(name string) string {
+		if pair, ok := names[api.resolver.resolveOrKeep(name)]; ok {
+			return pair
+		}
+		return api.resolver.resolveOrKeep(name)
+	}
+
+	tickers := make(map[string]*TickerValues, len(snapshot.Tickers))
+	for name, values := range snapshot.Tickers {
+		tickers[given(name)] = values
+	}
+	errs := make(map[string]error, len(snapshot.Errors))
+	for name, e := range snapshot.Errors {
+		errs[given(name)] = e
+	}
+	snapshot.Tickers, snapshot.Errors = tickers, errs
+	return snapshot, nil
+}
+
+func (api *resolvingPublic) OHLC(pair string, interval Interval, since int64) (*OHLCResponse, error) {
+	return api.public.OHLC(api.resolver.resolveOrKeep(pair), interval, since)
+}
+
+func (api *resolvingPublic) OHLCWithInterval(pair string, interval string, since int64) (*OHLCResponse, error) {
+	return api.public.OHLCWithInterval(api.resolver.resolveOrKeep(pair), interval, since)
+}
+
+func (api *resolvingPublic) OHLCMinutes(pair string) (*OHLCResponse, error) {
+	return api.public.OHLCMinutes(api.resolver.resolveOrKeep(pair))
+}
+
+func (api *resolvingPublic) Trades(pair string, since int64) (*TradesResponse, error) {
+	return api.public.Trades(api.resolver.resolveOrKeep(pair), since)
+}
+
+func (api *resolvingPublic) Depth(pair string, count int) (*OrderBook, error) {
+	return api.public.Depth(api.resolver.resolveOrKeep(pair), count)
+}
+
+// DepthMulti returns the books keyed by the pair names as given
+func (api *resolvingPublic) DepthMulti(ctx context.Context, pairs []string, count int) (map[string]*OrderBook, error) {
+	resolved := api.resolveAll(pairs)
+	books, err := api.public.DepthMulti(ctx, resolved, count)
+
+	given := make(map[string]*OrderBook, len(books))
+	for i, pair := range pairs {
+		if book, ok := books[resolved[i]]; ok {
+			given[pair] = book
+		}
+	}
+	if errs, ok := err.(DepthErrors); ok {
+		givenErrs := make(DepthErrors, len(errs))
+		for i, pair := range pairs {
+			if e, ok := errs[resolved[i]]; ok {
+				givenErrs[pair] = e
+			}
+		}
+		err = givenErrs
+	}
+	return given, err
+}
+
+func (api *resolvingPublic) Spread(pair string, since int64) (*SpreadResponse, error) {
+	return api.public.Spread(api.resolver.resolveOrKeep(pair), since)
+}
+
+func (api *resolvingPublic) SystemStatus() (*SystemStatusResponse, error) {
+	return api.public.SystemStatus()
+}
+
+// resolvingPrivate resolves the pairs of the wrapped PrivateAPI's calls. Like resolvingPublic
+// it implements every PrivateAPI method explicitly.
+type resolvingPrivate struct {
+	private  PrivateAPI
+	resolver *PairResolver
+}
+
+// resolveArg resolves the comma separated pairs of args["pair"]
+func (api *resolvingPrivate) resolveArg(args map[string]string) map[string]string {
+	value, ok := args["pair"]
+	if !ok {
+		return args
+	}
+
+	resolved := make(map[string]string, len(args))
+	for key, v := range args {
+		resolved[key] = v
+	}
+	pairs := strings.Split(value, ",")
+	for i, pair := range pairs {
+		pairs[i] = api.resolver.resolveOrKeep(strings.TrimSpace(pair))
+	}
+	resolved["pair"] = strings.Join(pairs, ",")
+	return resolved
+}
+
+// resolveOrders replaces the pairs of the orders' descriptions, which Kraken gives by altname, with their canonical names
+func (api *resolvingPrivate) resolveOrders(orders map[string]Order) {
+	for txid, order := range orders {
+		order.Description.AssetPair = api.resolver.resolveOrKeep(order.Description.AssetPair)
+		orders[txid] = order
+	}
+}
+
+// resolveFees keys fees by canonical pair names, which can be looked up in any form
+func (api *resolvingPrivate) resolveFees(fees Fees) Fees {
+	if fees == nil {
+		return nil
+	}
+	resolved := make(FeesMap)
+	for _, pair := range fees.GetPairs() {
+		resolved[api.resolver.resolveOrKeep(pair)] = fees.GetFeeInfo(pair)
+	}
+	return &resolvedFees{Fees: &resolved, resolver: api.resolver}
+}
+
+// TradesHistory returns the trades with their pairs by canonical name
+func (api *resolvingPrivate) TradesHistory(start int64, end int64, args map[string]string) (*TradesHistoryResponse, error) {
+	resp, err := api.private.TradesHistory(start, end, args)
+	if err != nil {
+		return nil, err
+	}
+	for txid, trade := range resp.Trades {
+		trade.AssetPair = api.resolver.resolveOrKeep(trade.AssetPair)
+		resp.Trades[txid] = trade
+	}
+	return resp, nil
+}
+
+func (api *resolvingPrivate) Balance() (BalanceResponse, error) {
+	return api.private.Balance()
+}
+
+func (api *resolvingPrivate) TradeBalance(args map[string]string) (*TradeBalanceResponse, error) {
+	return api.private.TradeBalance(args)
+}
+
+// TradeVolume returns the fees keyed by canonical pair names, which can be looked up in any form
+func (api *resolvingPrivate) TradeVolume(args map[string]string) (*TradeVolumeResponse, error) {
+	resp, err := api.private.TradeVolume(api.resolveArg(args))
+	if err != nil {
+		return nil, err
+	}
+	resp.Fees = api.resolveFees(resp.Fees)
+	resp.FeesMaker = api.resolveFees(resp.FeesMaker)
+	return resp, nil
+}
+
+// OpenOrders returns the orders with the pairs of their descriptions by canonical name
+func (api *resolvingPrivate) OpenOrders(args map[string]string) (*OpenOrdersResponse, error) {
+	resp, err := api.private.OpenOrders(args)
+	if err != nil {
+		return nil, err
+	}
+	api.resolveOrders(resp.Open)
+	return resp, nil
+}
+
+// ClosedOrders returns the orders with the pairs of their descriptions by canonical name
+func (api *resolvingPrivate) ClosedOrders(args map[string]string) (*ClosedOrdersResponse, error) {
+	resp, err := api.private.ClosedOrders(args)
+	if err != nil {
+		return nil, err
+	}
+	api.resolveOrders(resp.Closed)
+	return resp, nil
+}
+
+func (api *resolvingPrivate) CancelOrder(txid string) (*CancelOrderResponse, error) {
+	return api.private.CancelOrder(txid)
+}
+
+// QueryOrders returns the orders with the pairs of their descriptions by canonical name
+func (api *resolvingPrivate) QueryOrders(txids string, args map[string]string) (*QueryOrdersResponse, error) {
+	resp, err := api.private.QueryOrders(txids, args)
+	if err != nil {
+		return nil, err
+	}
+	api.resolveOrders(*resp)
+	return resp, nil
+}
+
+// AddOrder returns the order description with its pair by canonical name
+func (api *resolvingPrivate) AddOrder(pair string, direction string, orderType string, volume string, args map[string]string) (*AddOrderResponse, error) {
+	resp, err := api.private.AddOrder(api.resolver.resolveOrKeep(pair), direction, orderType, volume, args)
+	if err != nil {
+		return nil, err
+	}
+	resp.Description.AssetPair = api.resolver.resolveOrKeep(resp.Description.AssetPair)
+	return resp, nil
+}
+
+func (api *resolvingPrivate) Ledgers(args map[string]string) (*LedgersResponse, error) {
+	return api.private.Ledgers(args)
+}
+
+func (api *resolvingPrivate) DepositAddresses(asset string, method string) (*DepositAddressesResponse, error) {
+	return api.private.DepositAddresses(asset, method)
+}
+
+func (api *resolvingPrivate) Withdraw(asset string, key string, amount *big.Float) (*WithdrawResponse, error) {
+	return api.private.Withdraw(asset, key, amount)
+}
+
+func (api *resolvingPrivate) WithdrawInfo(asset string, key string, amount *big.Float) (*WithdrawInfoResponse, error) {
+	return api.private.WithdrawInfo(asset, key, amount)
+}
+
+func (api *resolvingPrivate) DiscoverPermissions() (PermissionSet, error) {
+	return api.private.DiscoverPermissions()
+}
+
+func (api *resolvingPrivate) Query(method string, args map[string]string) (interface{}, error) {
+	return api.private.Query(method, api.resolveArg(args))
+}
+
+// resolvedFees looks up fees by pair names in any form
+type resolvedFees struct {
+	Fees
+	resolver *PairResolver
+}
+
+func (f *resolvedFees) GetFeeInfo(pair string) FeeInfo {
+	return f.Fees.GetFeeInfo(f.resolver.resolveOrKeep(pair))
+}
+
+// resolvedTickers looks up tickers by pair names in any form
+type resolvedTickers struct {
+	TickerResponse
+	resolver *PairResolver
+}
+
+func (t *resolvedTickers) GetPairTickerInfo(pair string) PairTickerInfo {
+	return t.TickerResponse.GetPairTickerInfo(t.resolver.resolveOrKeep(pair))
+}
+
+// resolvedAssetPairs looks up asset pairs by pair names in any form
+type resolvedAssetPairs struct {
+	AssetPairsResponse
+	resolver *PairResolver
+}
+
+func (ap *resolvedAssetPairs) GetAssetPair(pair string) AssetPairInfo {
+	return ap.AssetPairsResponse.GetAssetPair(ap.resolver.resolveOrKeep(pair))
+}
@@ -0,0 +1,100 @@
+package krakenapi
+
+import (
+	"net/url"
+	"testing"
+)
+
+const testAssetPairs = `{"error":[],"result":{` +
+	`"XXBTZUSD":{"altname":"XBTUSD","wsname":"XBT/USD","base":"XXBT","quote":"ZUSD"},` +
+	`"XETHXXBT":{"altname":"ETHXBT","wsname":"ETH/XBT","base":"XETH","quote":"XXBT"},` +
+	`"XDGUSD":{"altname":"XDGUSD","wsname":"XDG/USD","base":"XXDG","quote":"ZUSD"}}}`
+
+func TestPairResolver(t *testing.T) {
+	api := newTestAPI(func(method string, values url.Values) string {
+		return testAssetPairs
+	})
+
+	r, err := LoadPairResolver(api.Public())
+	if err != nil {
+		t.Fatalf("LoadPairResolver() should not return an error, got %s", err)
+	}
+
+	for _, form := range []string{"XXBTZUSD", "XBTUSD", "XBT/USD", "BTC/USD", "btcusd"} {
+		if name, err := r.Resolve(form); err != nil || name != XXBTZUSD {
+			t.Errorf("Resolve(%s) should return XXBTZUSD, got %s %v", form, name, err)
+		}
+	}
+	if name, _ := r.Resolve("DOGE/USD"); name != "XDGUSD" {
+		t.Errorf("Resolve(DOGE/USD) should return XDGUSD, got %s", name)
+	}
+	if _, err := r.Resolve("FOO/BAR"); err == nil {
+		t.Errorf("Resolve(FOO/BAR) should fail")
+	}
+
+	if altname, _ := r.Altname("ETH/BTC"); altname != "ETHXBT" {
+		t.Errorf("Altname(ETH/BTC) should return ETHXBT, got %s", altname)
+	}
+	if wsname, _ := r.WSName("ETHXBT"); wsname != "ETH/XBT" {
+		t.Errorf("WSName(ETHXBT) should return ETH/XBT, got %s", wsname)
+	}
+	if human, _ := r.Human(XETHXXBT); human != "ETH/BTC" {
+		t.Errorf("Human(XETHXXBT) should return ETH/BTC, got %s", human)
+	}
+}
+
+func TestWithPairResolver(t *testing.T) {
+	var sent []string
+	api := newTestAPI(func(method string, values url.Values) string {
+		switch method {
+		case "AssetPairs":
+			return testAssetPairs
+		case "Ticker":
+			sent = append(sent, values.Get("pair"))
+			return `{"error":[],"result":{"XXBTZUSD":{"a":["30300.1","1","1"],"b":["30300","1","1"],"c":["30300","1"],` +
+				`"v":["10","10"],"p":["30200","30200"],"t":[1,2],"l":["29900","29900"],"h":["30400","30400"],"o":"30000"}}}`
+		case "AddOrder":
+			sent = append(sent, values.Get("pair"))
+			return `{"error":[],"result":{"descr":{"order":"buy 1.0 XBTUSD @ market","pair":"XBTUSD"},"txid":["OUF4EM-FRGI2-MQMWZD"]}}`
+		case "TradeVolume":
+			sent = append(sent, values.Get("pair"))
+			return `{"error":[],"result":{"currency":"ZUSD","volume":"0","fees":{"XXBTZUSD":{"fee":"0.2600"}}}}`
+		case "OpenOrders":
+			return `{"error":[],"result":{"open":{"OUF4EM-FRGI2-MQMWZD":{"status":"open","descr":{"pair":"XBTUSD"}}}}}`
+		}
+		return `{"error":["EGeneral:Invalid arguments"]}`
+	})
+
+	r, _ := LoadPairResolver(api.Public())
+	api = WithPairResolver(api, r)
+
+	ticker, err := api.Public().Ticker("BTC/USD")
+	if err != nil {
+		t.Fatalf("Ticker() should not return an error, got %s", err)
+	}
+	if ticker.GetPairTickerInfo("XBT/USD").OpeningPrice != 30000 {
+		t.Errorf("Ticker() response should resolve pair names, got %+v", ticker.GetPairTickerInfo("XBT/USD"))
+	}
+	snapshot, err := api.Public().MarketSnapshot("BTC/USD")
+	if err != nil || snapshot.Tickers["BTC/USD"] == nil {
+		t.Errorf("MarketSnapshot() should key tickers by the pair names as given, got %+v %v", snapshot, err)
+	}
+	order, err := api.Private().AddOrder("BTC/USD", "buy", OTMarket, "1", nil)
+	if err != nil {
+		t.Fatalf("AddOrder() should not return an error, got %s", err)
+	}
+	if order.Description.AssetPair != XXBTZUSD {
+		t.Errorf("AddOrder() should name the pair of the description canonically, got %s", order.Description.AssetPair)
+	}
+	volume, err := api.Private().TradeVolume(map[string]string{"pair": "BTC/USD"})
+	if err != nil || volume.Fees.GetFeeInfo("XBTUSD").Fee != 0.26 {
+		t.Errorf("TradeVolume() fees should be looked up in any form, got %+v %v", volume, err)
+	}
+	open, err := api.Private().OpenOrders(nil)
+	if err != nil || open.Open["OUF4EM-FRGI2-MQMWZD"].Description.AssetPair != XXBTZUSD {
+		t.Errorf("OpenOrders() should name the pairs of descriptions canonically, got %+v %v", open, err)
+	}
+	if len(sent) != 4 || sent[0] != XXBTZUSD || sent[1] != XXBTZUSD || sent[2] != XXBTZUSD || sent[3] != XXBTZUSD {
+		t.Errorf("Pairs should be sent by their canonical name, got %v", sent)
+	}
+}
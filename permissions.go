@@ -0,0 +1,172 @@
+package krakenapi
+
+import (
+	"math/big"
+	"strings"
+)
+
+// Permission is a single API key permission as shown in Kraken's key settings
+type Permission uint
+
+// Permissions that can be discovered for an API key
+const (
+	PermQueryFunds Permission = 1 << iota
+	PermWithdrawFunds
+	PermQueryOpenOrdersAndTrades
+	PermQueryClosedOrdersAndTrades
+	PermCreateModifyOrders
+	PermQueryLedgerEntries
+)
+
+// AllPermissions lists every permission DiscoverPermissions probes for
+var AllPermissions = []Permission{
+	PermQueryFunds,
+	PermWithdrawFunds,
+	PermQueryOpenOrdersAndTrades,
+	PermQueryClosedOrdersAndTrades,
+	PermCreateModifyOrders,
+	PermQueryLedgerEntries,
+}
+
+var permissionNames = map[Permission]string{
+	PermQueryFunds:                 "Query Funds",
+	PermWithdrawFunds:              "Withdraw Funds",
+	PermQueryOpenOrdersAndTrades:   "Query Open Orders & Trades",
+	PermQueryClosedOrdersAndTrades: "Query Closed Orders & Trades",
+	PermCreateModifyOrders:         "Create & Modify Orders",
+	PermQueryLedgerEntries:         "Query Ledger Entries",
+}
+
+func (p Permission) String() string {
+	if name, ok := permissionNames[p]; ok {
+		return name
+	}
+	return "Unknown Permission"
+}
+
+// PermissionSet is a set of API key permissions
+type PermissionSet uint
+
+// NewPermissionSet creates a set holding the given permissions
+func NewPermissionSet(perms ...Permission) PermissionSet {
+	var set PermissionSet
+	for _, p := range perms {
+		set |= PermissionSet(p)
+	}
+	return set
+}
+
+// Has returns true if the set contains the given permission
+func (s PermissionSet) Has(p Permission) bool {
+	return s&PermissionSet(p) != 0
+}
+
+// Missing returns the permissions of required that are not in the set
+func (s PermissionSet) Missing(required PermissionSet) PermissionSet {
+	return required &^ s
+}
+
+// Excess returns the permissions in the set that are not in allowed
+func (s PermissionSet) Excess(allowed PermissionSet) PermissionSet {
+	return s &^ allowed
+}
+
+// Permissions returns the permissions of the set in a stable order
+func (s PermissionSet) Permissions() []Permission {
+	var perms []Permission
+	for _, p := range AllPermissions {
+		if s.Has(p) {
+			perms = append(perms, p)
+		}
+	}
+	return perms
+}
+
+func (s PermissionSet) String() string {
+	var names []string
+	for _, p := range s.Permissions() {
+		names = append(names, p.String())
+	}
+	return "[" + strings.Join(names, ", ") + "]"
+}
+
+// probePair is the pair of the order probe, given by its altname which Kraken resolves itself
+const probePair = "XBTUSD"
+
+// permissionProbes maps each permission to a call that needs it but has no side effects
+var permissionProbes = []struct {
+	permission Permission
+	probe      func(api PrivateAPI) error
+}{
+	{PermQueryFunds, func(api PrivateAPI) error {
+		_, err := api.Balance()
+		return err
+	}},
+	{PermWithdrawFunds, func(api PrivateAPI) error {
+		_, err := api.WithdrawInfo("XXBT", "", big.NewFloat(0))
+		return err
+	}},
+	{PermQueryOpenOrdersAndTrades, func(api PrivateAPI) error {
+		_, err := api.OpenOrders(nil)
+		return err
+	}},
+	{PermQueryClosedOrdersAndTrades, func(api PrivateAPI) error {
+		_, err := api.ClosedOrders(nil)
+		return err
+	}},
+	{PermCreateModifyOrders, func(api PrivateAPI) error {
+		// validate=true only checks the order, it is never placed
+		_, err := api.AddOrder(probePair, "buy", OTLimit, "1", map[string]string{
+			"price":    "1",
+			"validate": "true",
+		})
+		return err
+	}},
+	{PermQueryLedgerEntries, func(api PrivateAPI) error {
+		_, err := api.Ledgers(nil)
+		return err
+	}},
+}
+
+// DiscoverPermissions probes harmless calls to find out which permissions the API key has.
+// A probe counts as granted if it succeeds or Kraken rejects it with an order or funding error,
+// as the withdraw and order probes are meant to fail that way. Any other error, including the
+// generic EGeneral:Invalid arguments, leaves the permission unknown and is returned.
+func (api *KrakenPrivate) DiscoverPermissions() (PermissionSet, error) {
+	var set PermissionSet
+	for _, p := range permissionProbes {
+		err := p.probe(api)
+		switch {
+		case err == nil || isRejectedArgumentError(err):
+			set |= PermissionSet(p.permission)
+		case isPermissionError(err):
+		default:
+			return 0, err
+		}
+	}
+	return set, nil
+}
+
+// rejectedArgumentErrors are the endpoint specific Kraken errors raised after the key was allowed to make the call
+var rejectedArgumentErrors = []string{
+	"EOrder:",
+	"EFunding:",
+}
+
+// isRejectedArgumentError returns true if the order or funding endpoint refused the arguments or funds of an allowed call
+func isRejectedArgumentError(err error) bool {
+	if !strings.Contains(err.Error(), "Could not execute request! #7") {
+		return false
+	}
+	for _, prefix := range rejectedArgumentErrors {
+		if strings.Contains(err.Error(), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isPermissionError returns true if Kraken refused the call because of missing key permissions
+func isPermissionError(err error) bool {
+	return strings.Contains(err.Error(), "EGeneral:Permission denied")
+}
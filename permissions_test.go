@@ -0,0 +1,74 @@
+package krakenapi
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestDiscoverPermissions(t *testing.T) {
+	api := newTestAPI(func(method string, values url.Values) string {
+		switch method {
+		case "Balance", "OpenOrders":
+			return `{"error":[],"result":{}}`
+		case "AddOrder":
+			if values.Get("validate") != "true" || values.Get("pair") != "XBTUSD" {
+				t.Errorf("AddOrder probe should only validate, got %v", values)
+			}
+			return `{"error":["EOrder:Insufficient funds"]}`
+		case "WithdrawInfo":
+			return `{"error":["EFunding:Unknown withdraw key"]}`
+		}
+		return `{"error":["EGeneral:Permission denied"]}`
+	})
+
+	perms, err := api.Private().DiscoverPermissions()
+	if err != nil {
+		t.Fatalf("DiscoverPermissions() should not return an error, got %s", err)
+	}
+
+	expected := NewPermissionSet(PermQueryFunds, PermQueryOpenOrdersAndTrades, PermCreateModifyOrders, PermWithdrawFunds)
+	if perms != expected {
+		t.Errorf("DiscoverPermissions() should return %s, got %s", expected, perms)
+	}
+
+	required := NewPermissionSet(PermQueryFunds, PermQueryLedgerEntries)
+	if missing := perms.Missing(required); missing != NewPermissionSet(PermQueryLedgerEntries) {
+		t.Errorf("Missing() should return the ledger permission, got %s", missing)
+	}
+	if excess := perms.Excess(required); excess.Has(PermQueryFunds) || !excess.Has(PermWithdrawFunds) {
+		t.Errorf("Excess() should return unrequired permissions only, got %s", excess)
+	}
+}
+
+func TestDiscoverPermissionsInvalidKey(t *testing.T) {
+	api := newTestAPI(func(method string, values url.Values) string {
+		return `{"error":["EAPI:Invalid key"]}`
+	})
+
+	if _, err := api.Private().DiscoverPermissions(); err == nil {
+		t.Errorf("DiscoverPermissions() should fail for an invalid key")
+	}
+}
+
+func TestDiscoverPermissionsUnavailable(t *testing.T) {
+	api := newTestAPI(func(method string, values url.Values) string {
+		return `{"error":["EService:Unavailable"]}`
+	})
+
+	if perms, err := api.Private().DiscoverPermissions(); err == nil {
+		t.Errorf("DiscoverPermissions() should fail while the service is unavailable, got %s", perms)
+	}
+}
+
+func TestDiscoverPermissionsInvalidArguments(t *testing.T) {
+	api := newTestAPI(func(method string, values url.Values) string {
+		if method == "WithdrawInfo" {
+			return `{"error":["EGeneral:Invalid arguments"]}`
+		}
+		return `{"error":[],"result":{}}`
+	})
+
+	if perms, err := api.Private().DiscoverPermissions(); err == nil {
+		t.Errorf("DiscoverPermissions() should not count a generic error as granted, got %s", perms)
+	}
+}
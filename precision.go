@@ -0,0 +1,164 @@
+package krakenapi
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// RoundingMode selects how prices and volumes are rounded to a pair's precision
+type RoundingMode int
+
+// RoundingMode values
+const (
+	// Round to the nearest step, halves away from zero
+	RoundNearest RoundingMode = iota
+	// Round down
+	RoundFloor
+	// Round up
+	RoundCeil
+)
+
+// RoundPrice rounds price to the pair's tick size, or to its pair decimals if it has no tick size.
+// Prices and steps are taken at their shortest decimal form and rounded exactly.
+func (info AssetPairInfo) RoundPrice(price float64, mode RoundingMode) float64 {
+	return roundFloat(price, info.priceStep(), mode)
+}
+
+// RoundVolume rounds volume to the pair's lot decimals
+func (info AssetPairInfo) RoundVolume(volume float64, mode RoundingMode) float64 {
+	return roundFloat(volume, decimalStep(info.LotDecimals), mode)
+}
+
+// FormatPrice rounds price like RoundPrice and formats it for AddOrder, never in scientific notation
+func (info AssetPairInfo) FormatPrice(price float64, mode RoundingMode) string {
+	return formatRounded(price, info.priceStep(), info.priceDecimals(), mode)
+}
+
+// FormatVolume rounds volume like RoundVolume and formats it for AddOrder, never in scientific notation
+func (info AssetPairInfo) FormatVolume(volume float64, mode RoundingMode) string {
+	return formatRounded(volume, decimalStep(info.LotDecimals), info.LotDecimals, mode)
+}
+
+// CheckOrder checks an order of volume at price against the pair's precision and minimums.
+// The price may be 0 for market orders, which skips the price checks. It returns a *RiskError.
+func (info AssetPairInfo) CheckOrder(volume, price float64) error {
+	if volume <= 0 || math.IsNaN(volume) || math.IsInf(volume, 0) {
+		return &RiskError{RuleInvalidVolume, fmt.Sprintf("volume %v is not a positive number", volume)}
+	}
+	if volume < info.OrderMin {
+		return &RiskError{RuleOrderMin, fmt.Sprintf("volume %v is below the minimum of %v", volume, info.OrderMin)}
+	}
+	if !onStep(volume, decimalStep(info.LotDecimals)) {
+		return &RiskError{RuleLotDecimals, fmt.Sprintf("volume %v has more than %d decimals", volume, info.LotDecimals)}
+	}
+	if price == 0 {
+		return nil
+	}
+
+	if price < 0 || math.IsNaN(price) || math.IsInf(price, 0) {
+		return &RiskError{RuleInvalidPrice, fmt.Sprintf("price %v is not a positive number", price)}
+	}
+	if step := info.priceStep(); !onStep(price, step) {
+		return &RiskError{RulePriceDecimals, fmt.Sprintf("price %v is not a multiple of %s", price, step.FloatString(info.priceDecimals()))}
+	}
+	if cost := volume * price; cost < info.CostMin {
+		return &RiskError{RuleCostMin, fmt.Sprintf("cost %v is below the minimum of %v", cost, info.CostMin)}
+	}
+	return nil
+}
+
+// priceStep returns the smallest price increment of the pair
+func (info AssetPairInfo) priceStep() *big.Rat {
+	if step := exactRat(info.TickSize); info.TickSize > 0 && step != nil {
+		return step
+	}
+	return decimalStep(info.PairDecimals)
+}
+
+// priceDecimals returns the number of decimals of prices, which the tick size may have more of than PairDecimals
+func (info AssetPairInfo) priceDecimals() int {
+	decimals := info.PairDecimals
+	if info.TickSize > 0 {
+		if tick := countDecimals(strconv.FormatFloat(info.TickSize, 'f', -1, 64)); tick > decimals {
+			decimals = tick
+		}
+	}
+	return decimals
+}
+
+// roundDecimals rounds value to given decimals
+func roundDecimals(value float64, decimals int, mode RoundingMode) float64 {
+	return roundFloat(value, decimalStep(decimals), mode)
+}
+
+// roundFloat rounds value to a multiple of step. NaN and infinities are returned unchanged.
+func roundFloat(value float64, step *big.Rat, mode RoundingMode) float64 {
+	exact := exactRat(value)
+	if exact == nil {
+		return value
+	}
+	rounded, _ := roundRat(exact, step, mode).Float64()
+	return rounded
+}
+
+// formatRounded rounds value to a multiple of step and formats it with given decimals
+func formatRounded(value float64, step *big.Rat, decimals int, mode RoundingMode) string {
+	exact := exactRat(value)
+	if exact == nil {
+		return strconv.FormatFloat(value, 'f', decimals, 64)
+	}
+	return roundRat(exact, step, mode).FloatString(decimals)
+}
+
+// roundRat rounds value to a multiple of step
+func roundRat(value, step *big.Rat, mode RoundingMode) *big.Rat {
+	steps := new(big.Rat).Quo(value, step)
+	quo, rem := new(big.Int).QuoRem(steps.Num(), steps.Denom(), new(big.Int))
+	if rem.Sign() != 0 {
+		// quo is truncated toward zero
+		away := false
+		switch mode {
+		case RoundFloor:
+			away = steps.Sign() < 0
+		case RoundCeil:
+			away = steps.Sign() > 0
+		default:
+			// Halves and above, |rem| / denom >= 1/2
+			away = new(big.Int).Lsh(rem.Abs(rem), 1).Cmp(steps.Denom()) >= 0
+		}
+		if away {
+			quo.Add(quo, big.NewInt(int64(steps.Sign())))
+		}
+	}
+	return new(big.Rat).Mul(new(big.Rat).SetInt(quo), step)
+}
+
+// onStep returns true if value is a multiple of step
+func onStep(value float64, step *big.Rat) bool {
+	exact := exactRat(value)
+	return exact != nil && new(big.Rat).Quo(exact, step).IsInt()
+}
+
+// exactRat returns the exact value of f in its shortest decimal form, e.g. 0.29 rather than the
+// nearest binary fraction, nil for NaN and infinities
+func exactRat(f float64) *big.Rat {
+	return DecimalFromFloat(f).Rat()
+}
+
+// decimalStep returns the step of numbers with given decimals, 10^-decimals
+func decimalStep(decimals int) *big.Rat {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	return new(big.Rat).SetFrac(big.NewInt(1), scale)
+}
+
+// countDecimals returns the number of significant decimals of a number string
+func countDecimals(number string) int {
+	i := strings.IndexByte(number, '.')
+	if i < 0 {
+		return 0
+	}
+	return len(strings.TrimRight(number[i+1:], "0"))
+}
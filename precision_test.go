@@ -0,0 +1,93 @@
+package krakenapi
+
+import (
+	"testing"
+)
+
+func TestRoundPrice(t *testing.T) {
+	info := AssetPairInfo{PairDecimals: 2, LotDecimals: 8}
+	tests := []struct {
+		price    float64
+		mode     RoundingMode
+		expected string
+	}{
+		{0.29, RoundFloor, "0.29"},
+		{0.29, RoundCeil, "0.29"},
+		{1.005, RoundFloor, "1.00"},
+		{1.001, RoundCeil, "1.01"},
+		{1.236, RoundNearest, "1.24"},
+		{1.005, RoundNearest, "1.01"},
+		{-1.005, RoundNearest, "-1.01"},
+		{1.0049, RoundNearest, "1.00"},
+		{-0.001, RoundNearest, "0.00"},
+		{12345678.9, RoundNearest, "12345678.90"},
+		{0.0000001, RoundCeil, "0.01"},
+	}
+	for _, test := range tests {
+		if formatted := info.FormatPrice(test.price, test.mode); formatted != test.expected {
+			t.Errorf("FormatPrice(%v, %d) should return %s, got %s", test.price, test.mode, test.expected, formatted)
+		}
+	}
+
+	ticked := AssetPairInfo{PairDecimals: 1, TickSize: 0.5}
+	if price := ticked.RoundPrice(20000.7, RoundNearest); price != 20000.5 {
+		t.Errorf("RoundPrice() should round to the tick size, got %v", price)
+	}
+	if price := ticked.RoundPrice(20000.1, RoundCeil); price != 20000.5 {
+		t.Errorf("RoundPrice() should round up to the tick size, got %v", price)
+	}
+	fine := AssetPairInfo{PairDecimals: 4, TickSize: 0.00001}
+	if formatted := fine.FormatPrice(0.123456, RoundFloor); formatted != "0.12345" {
+		t.Errorf("FormatPrice() should keep the decimals of the tick size, got %s", formatted)
+	}
+}
+
+func TestRoundVolume(t *testing.T) {
+	info := AssetPairInfo{LotDecimals: 8}
+	if formatted := info.FormatVolume(0.00000001, RoundNearest); formatted != "0.00000001" {
+		t.Errorf("FormatVolume() should not use scientific notation, got %s", formatted)
+	}
+	if formatted := info.FormatVolume(0.123456789, RoundFloor); formatted != "0.12345678" {
+		t.Errorf("FormatVolume() should round down, got %s", formatted)
+	}
+	if formatted := info.FormatVolume(0.123456781, RoundCeil); formatted != "0.12345679" {
+		t.Errorf("FormatVolume() should round up, got %s", formatted)
+	}
+	if formatted := info.FormatVolume(1234567.12345678, RoundFloor); formatted != "1234567.12345678" {
+		t.Errorf("FormatVolume() should keep large volumes on a lot exactly, got %s", formatted)
+	}
+	if formatted := info.FormatVolume(1234567.123456785, RoundNearest); formatted != "1234567.12345679" {
+		t.Errorf("FormatVolume() should round halves of large volumes away from zero, got %s", formatted)
+	}
+}
+
+func TestCheckOrderPrecision(t *testing.T) {
+	info := AssetPairInfo{PairDecimals: 1, LotDecimals: 8, OrderMin: 0.0001, CostMin: 0.5, TickSize: 0.1}
+	tests := []struct {
+		volume float64
+		price  float64
+		rule   RiskRule
+	}{
+		{0.1, 20000.1, ""},
+		{0.1, 0, ""},
+		{0, 20000, RuleInvalidVolume},
+		{0.00001, 20000, RuleOrderMin},
+		{0.123456789, 20000, RuleLotDecimals},
+		{1234567.12345678, 20000.1, ""},
+		{0.1, -1, RuleInvalidPrice},
+		{0.1, 20000.05, RulePriceDecimals},
+		{0.0001, 1, RuleCostMin},
+	}
+	for _, test := range tests {
+		err := info.CheckOrder(test.volume, test.price)
+		if test.rule == "" {
+			if err != nil {
+				t.Errorf("CheckOrder(%v, %v) should pass, got %s", test.volume, test.price, err)
+			}
+			continue
+		}
+		if riskErr, ok := err.(*RiskError); !ok || riskErr.Rule != test.rule {
+			t.Errorf("CheckOrder(%v, %v) should be rejected by %s, got %v", test.volume, test.price, test.rule, err)
+		}
+	}
+}
@@ -57,6 +57,8 @@ type PrivateAPI interface {
 	DepositAddresses(asset string, method string) (*DepositAddressesResponse, error)
 	Withdraw(asset string, key string, amount *big.Float) (*WithdrawResponse, error)
 	WithdrawInfo(asset string, key string, amount *big.Float) (*WithdrawInfoResponse, error)
+	DiscoverPermissions() (PermissionSet, error)
+	Query(method string, args map[string]string) (interface{}, error)
 }
 
 // krakenAPI represents a Kraken API Client connection
@@ -346,6 +348,30 @@ func (api *KrakenPrivate) WithdrawInfo(asset string, key string, amount *big.Flo
 	return resp.(*WithdrawInfoResponse), nil
 }
 
+// Query executes any private method by name and returns the untyped result
+func (api *KrakenPrivate) Query(method string, args map[string]string) (interface{}, error) {
+	if !isPrivateMethod(method) {
+		return nil, fmt.Errorf("Method %s is not a valid private method", method)
+	}
+
+	params := url.Values{}
+	for key, value := range args {
+		params.Add(key, value)
+	}
+
+	return api.queryPrivate(method, params, nil)
+}
+
+// isPrivateMethod returns true if method is a known private method
+func isPrivateMethod(method string) bool {
+	for _, m := range privateMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
 // queryPrivate executes a private method query
 func (api *KrakenPrivate) queryPrivate(method string, values url.Values, typ interface{}) (interface{}, error) {
 	urlPath := fmt.Sprintf("/%s/private/%s", APIVersion, method)
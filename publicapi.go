@@ -1,11 +1,14 @@
 package krakenapi
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // List of valid public methods
@@ -16,6 +19,7 @@ var publicMethods = []string{
 	"OHLC",
 	"OHLCMinutes",
 	"Spread",
+	"SystemStatus",
 	"Ticker",
 	"Time",
 	"Trades",
@@ -23,13 +27,20 @@ var publicMethods = []string{
 
 type PublicAPI interface {
 	Time() (*TimeResponse, error)
-	Assets() (AssetsResponse, error)
-	AssetPairs() (AssetPairsResponse, error)
+	Assets(assets ...string) (AssetsResponse, error)
+	AssetsWithClass(aclass string, assets ...string) (AssetsResponse, error)
+	AssetPairs(pairs ...string) (AssetPairsResponse, error)
+	AssetPairsWithInfo(info PairInfoMode, pairs ...string) (AssetPairsResponse, error)
 	Ticker(pairs ...string) (TickerResponse, error)
-	OHLC(pair string, interval string, since int64) (*OHLCResponse, error)
+	MarketSnapshot(pairs ...string) (*MarketSnapshot, error)
+	OHLC(pair string, interval Interval, since int64) (*OHLCResponse, error)
+	OHLCWithInterval(pair string, interval string, since int64) (*OHLCResponse, error)
 	OHLCMinutes(pair string) (*OHLCResponse, error)
 	Trades(pair string, since int64) (*TradesResponse, error)
 	Depth(pair string, count int) (*OrderBook, error)
+	DepthMulti(ctx context.Context, pairs []string, count int) (map[string]*OrderBook, error)
+	Spread(pair string, since int64) (*SpreadResponse, error)
+	SystemStatus() (*SystemStatusResponse, error)
 }
 
 // krakenAPI represents a Kraken API Client connection
@@ -47,9 +58,32 @@ func (api *KrakenPublic) Time() (*TimeResponse, error) {
 	return resp.(*TimeResponse), nil
 }
 
-// Assets returns the servers available assets
-func (api *KrakenPublic) Assets() (AssetsResponse, error) {
-	resp, err := api.queryPublic("Assets", nil, &Assets{})
+// SystemStatus returns the current system status or trading mode
+func (api *KrakenPublic) SystemStatus() (*SystemStatusResponse, error) {
+	resp, err := api.queryPublic("SystemStatus", nil, &SystemStatusResponse{})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.(*SystemStatusResponse), nil
+}
+
+// Assets returns the servers available assets, all of them if no assets are given
+func (api *KrakenPublic) Assets(assets ...string) (AssetsResponse, error) {
+	return api.AssetsWithClass("", assets...)
+}
+
+// AssetsWithClass returns the servers available assets of given asset class,
+// all of them if no assets are given. An empty class returns all classes.
+func (api *KrakenPublic) AssetsWithClass(aclass string, assets ...string) (AssetsResponse, error) {
+	values := url.Values{}
+	if len(assets) > 0 {
+		values.Set("asset", strings.Join(assets, ","))
+	}
+	if aclass != "" {
+		values.Set("aclass", aclass)
+	}
+	resp, err := api.queryPublic("Assets", values, &Assets{})
 	if err != nil {
 		return nil, err
 	}
@@ -57,9 +91,22 @@ func (api *KrakenPublic) Assets() (AssetsResponse, error) {
 	return resp.(AssetsResponse), nil
 }
 
-// AssetPairs returns the servers available asset pairs
-func (api *KrakenPublic) AssetPairs() (AssetPairsResponse, error) {
-	resp, err := api.queryPublic("AssetPairs", nil, &AssetPairs{})
+// AssetPairs returns the servers available asset pairs, all of them if no pairs are given
+func (api *KrakenPublic) AssetPairs(pairs ...string) (AssetPairsResponse, error) {
+	return api.AssetPairsWithInfo("", pairs...)
+}
+
+// AssetPairsWithInfo returns the given info mode of the servers available asset pairs,
+// all of them if no pairs are given. An empty info mode returns all info.
+func (api *KrakenPublic) AssetPairsWithInfo(info PairInfoMode, pairs ...string) (AssetPairsResponse, error) {
+	values := url.Values{}
+	if len(pairs) > 0 {
+		values.Set("pair", strings.Join(pairs, ","))
+	}
+	if info != "" {
+		values.Set("info", string(info))
+	}
+	resp, err := api.queryPublic("AssetPairs", values, &AssetPairs{})
 	if err != nil {
 		return nil, err
 	}
@@ -67,11 +114,13 @@ func (api *KrakenPublic) AssetPairs() (AssetPairsResponse, error) {
 	return resp.(AssetPairsResponse), nil
 }
 
-// Ticker returns the ticker for given comma separated pairs
+// Ticker returns the ticker for given comma separated pairs, all pairs if none are given
 func (api *KrakenPublic) Ticker(pairs ...string) (TickerResponse, error) {
-	resp, err := api.queryPublic("Ticker", url.Values{
-		"pair": {strings.Join(pairs, ",")},
-	}, &Tickers{})
+	values := url.Values{}
+	if len(pairs) > 0 {
+		values.Set("pair", strings.Join(pairs, ","))
+	}
+	resp, err := api.queryPublic("Ticker", values, &Tickers{})
 	if err != nil {
 		return nil, err
 	}
@@ -79,25 +128,45 @@ func (api *KrakenPublic) Ticker(pairs ...string) (TickerResponse, error) {
 	return resp.(TickerResponse), nil
 }
 
-// OHLCWithInterval returns a OHLCResponse struct based on the given pair
-func (api *KrakenPublic) OHLC(pair string, interval string, since int64) (*OHLCResponse, error) {
+// MarketSnapshot returns the parsed tickers for given pairs, all pairs if none are given
+func (api *KrakenPublic) MarketSnapshot(pairs ...string) (*MarketSnapshot, error) {
+	tickers, err := api.Ticker(pairs...)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMarketSnapshot(tickers, time.Now())
+}
+
+// OHLCWithInterval returns a OHLCResponse struct based on the given pair and interval in minutes
+//
+// Deprecated: use OHLC with an Interval
+func (api *KrakenPublic) OHLCWithInterval(pair string, interval string, since int64) (*OHLCResponse, error) {
+	var i Interval
+	if interval != "" {
+		var err error
+		if i, err = ParseInterval(interval); err != nil {
+			return nil, err
+		}
+	}
+	return api.OHLC(pair, i, since)
+}
+
+// OHLC returns a OHLCResponse struct based on the given pair and interval, 1 minute if zero
+func (api *KrakenPublic) OHLC(pair string, interval Interval, since int64) (*OHLCResponse, error) {
 	urlValue := url.Values{}
 	urlValue.Add("pair", pair)
 
 	if since > 0 {
 		urlValue.Add("since", fmt.Sprintf("%d", since))
 	}
-	if interval == "" {
-		urlValue.Add("interval", "1")
-	} else {
-		switch interval {
-		// supported values https://www.kraken.com/features/api#get-ohlc-data
-		case "1", "5", "15", "30", "60", "240", "1440", "10080", "21600":
-			urlValue.Add("interval", interval)
-		default:
-			return nil, fmt.Errorf("Unsupported value for Interval: " + interval)
-		}
+	if interval == 0 {
+		interval = Interval1m
 	}
+	if !interval.Valid() {
+		return nil, fmt.Errorf("Unsupported value for Interval: %d", interval)
+	}
+	urlValue.Add("interval", interval.String())
 
 	// Returns a map[string]interface{} as an interface{}
 	interfaceResponse, err := api.queryPublic("OHLC", urlValue, nil)
@@ -108,7 +177,10 @@ func (api *KrakenPublic) OHLC(pair string, interval string, since int64) (*OHLCR
 	// Converts the interface into map[string]interface{}
 	mapResponse := interfaceResponse.(map[string]interface{})
 	// Extracts the list of OHLC from the map to build a slice of interfaces
-	OHLCsUnstructured := mapResponse[pair].([]interface{})
+	OHLCsUnstructured, ok := pairResult(mapResponse, pair).([]interface{})
+	if !ok {
+		return nil, errors.New("invalid response")
+	}
 
 	ret := new(OHLCResponse)
 	for _, OHLCInterfaceSlice := range OHLCsUnstructured {
@@ -129,7 +201,7 @@ func (api *KrakenPublic) OHLC(pair string, interval string, since int64) (*OHLCR
 // OHLC returns a OHLCResponse struct based on the given pair
 // Backward compatible with previous version
 func (api *KrakenPublic) OHLCMinutes(pair string) (*OHLCResponse, error) {
-	ret, err := api.OHLC(pair, "1", 0)
+	ret, err := api.OHLC(pair, Interval1m, 0)
 
 	return ret, err
 }
@@ -157,7 +229,10 @@ func (api *KrakenPublic) Trades(pair string, since int64) (*TradesResponse, erro
 		Trades: make([]TradeInfo, 0),
 	}
 
-	trades := v[pair].([]interface{})
+	trades, ok := pairResult(v, pair).([]interface{})
+	if !ok {
+		return nil, errors.New("invalid response")
+	}
 	for _, v := range trades {
 		trade := v.([]interface{})
 
@@ -188,8 +263,12 @@ func (api *KrakenPublic) Trades(pair string, since int64) (*TradesResponse, erro
 
 // Depth returns the order book for given pair and orders count.
 func (api *KrakenPublic) Depth(pair string, count int) (*OrderBook, error) {
+	return api.depth(context.Background(), pair, count)
+}
+
+func (api *KrakenPublic) depth(ctx context.Context, pair string, count int) (*OrderBook, error) {
 	dr := DepthResponse{}
-	_, err := api.queryPublic("Depth", url.Values{
+	_, err := api.queryPublicContext(ctx, "Depth", url.Values{
 		"pair": {pair}, "count": {strconv.Itoa(count)},
 	}, &dr)
 
@@ -201,13 +280,162 @@ func (api *KrakenPublic) Depth(pair string, count int) (*OrderBook, error) {
 		return &book, nil
 	}
 
+	// Kraken keys the book by the canonical pair name, even if pair is the altname
+	if len(dr) == 1 {
+		for _, book := range dr {
+			return &book, nil
+		}
+	}
+
 	return nil, errors.New("invalid response")
 }
 
+// depthConcurrency is the maximum number of Depth requests DepthMulti sends at once
+const depthConcurrency = 4
+
+// depthRate is the number of Depth requests per second DepthMulti sends if the client has no rate limiter,
+// which keeps it within Kraken's public rate limit
+const depthRate = 1.0
+
+// DepthMulti returns the order books for given pairs and orders count, fetched concurrently.
+// Books are keyed by the pair names as given. If some pairs fail the books of the others
+// are still returned, together with a DepthErrors holding the error of each failed pair.
+// Requests are paced by the client's rate limiter, or at depthRate per second if it has none.
+func (api *KrakenPublic) DepthMulti(ctx context.Context, pairs []string, count int) (map[string]*OrderBook, error) {
+	if api.limiter == nil {
+		paced := *api
+		paced.limiter = NewRateLimiter(depthRate, depthConcurrency)
+		api = &paced
+	}
+
+	type result struct {
+		pair string
+		book *OrderBook
+		err  error
+	}
+
+	results := make(chan result, len(pairs))
+	slots := make(chan struct{}, depthConcurrency)
+	for _, pair := range pairs {
+		go func(pair string) {
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-ctx.Done():
+				results <- result{pair: pair, err: ctx.Err()}
+				return
+			}
+			book, err := api.depth(ctx, pair, count)
+			results <- result{pair, book, err}
+		}(pair)
+	}
+
+	books := make(map[string]*OrderBook)
+	errs := make(DepthErrors)
+	for range pairs {
+		r := <-results
+		if r.err != nil {
+			errs[r.pair] = r.err
+		} else {
+			books[r.pair] = r.book
+		}
+	}
+
+	if len(errs) > 0 {
+		return books, errs
+	}
+	return books, nil
+}
+
+// DepthErrors holds the error of each pair DepthMulti failed to fetch
+type DepthErrors map[string]error
+
+func (e DepthErrors) Error() string {
+	pairs := make([]string, 0, len(e))
+	for pair := range e {
+		pairs = append(pairs, pair)
+	}
+	sort.Strings(pairs)
+
+	msgs := make([]string, 0, len(e))
+	for _, pair := range pairs {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", pair, e[pair]))
+	}
+	return fmt.Sprintf("Depth failed for %d pairs (%s)", len(e), strings.Join(msgs, "; "))
+}
+
+// Spread returns the recent spreads for given pair
+func (api *KrakenPublic) Spread(pair string, since int64) (*SpreadResponse, error) {
+	values := url.Values{"pair": {pair}}
+	if since > 0 {
+		values.Set("since", strconv.FormatInt(since, 10))
+	}
+	resp, err := api.queryPublic("Spread", values, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	v := resp.(map[string]interface{})
+
+	result := &SpreadResponse{
+		Pair:    pair,
+		Last:    int64(v["last"].(float64)),
+		Spreads: make([]SpreadItem, 0),
+	}
+
+	spreads, ok := pairResult(v, pair).([]interface{})
+	if !ok {
+		return nil, errors.New("invalid response")
+	}
+	for _, v := range spreads {
+		spread := v.([]interface{})
+		if len(spread) < 3 {
+			return nil, fmt.Errorf("the length is not 3 but %d", len(spread))
+		}
+
+		bid, err := ParseDecimal(spread[1].(string))
+		if err != nil {
+			return nil, err
+		}
+		ask, err := ParseDecimal(spread[2].(string))
+		if err != nil {
+			return nil, err
+		}
+
+		result.Spreads = append(result.Spreads, SpreadItem{
+			Time: time.Unix(int64(spread[0].(float64)), 0),
+			Bid:  bid,
+			Ask:  ask,
+		})
+	}
+
+	return result, nil
+}
+
+// pairResult returns the result for pair from a response also holding the `last` cursor.
+// Kraken keys results by the canonical pair name, so if pair was given by its altname
+// the only other key is used instead.
+func pairResult(resp map[string]interface{}, pair string) interface{} {
+	if result, ok := resp[pair]; ok {
+		return result
+	}
+	for key, result := range resp {
+		if key != "last" {
+			return result
+		}
+	}
+	return nil
+}
+
 // Execute a public method query
 func (api *KrakenPublic) queryPublic(method string, values url.Values, typ interface{}) (interface{}, error) {
+	return api.queryPublicContext(context.Background(), method, values, typ)
+}
+
+// Execute a public method query with a context
+func (api *KrakenPublic) queryPublicContext(ctx context.Context, method string, values url.Values, typ interface{}) (interface{}, error) {
 	apiUrl := fmt.Sprintf("%s/%s/public/%s", APIURL, APIVersion, method)
-	resp, err := api.doRequest(apiUrl, values, nil, typ)
+	resp, err := api.doRequestContext(ctx, apiUrl, values, nil, typ)
 
 	return resp, err
 }
@@ -0,0 +1,134 @@
+package krakenapi
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ErrReadOnly is returned by read-only clients for calls that would change the account
+var ErrReadOnly = errors.New("krakenapi: client is read-only")
+
+// readOnlyMethods lists the private methods that only read account data. Every other
+// method, including ones Kraken adds later, is treated as mutating.
+var readOnlyMethods = map[string]bool{
+	"Balance":        true,
+	"BalanceMap":     true,
+	"ClosedOrders":   true,
+	"DepositMethods": true,
+	"DepositStatus":  true,
+	"ExportStatus":   true,
+	"Ledgers":        true,
+	"OpenOrders":     true,
+	"OpenPositions":  true,
+	"QueryLedgers":   true,
+	"QueryOrders":    true,
+	"QueryTrades":    true,
+	"RetrieveExport": true,
+	"TradeBalance":   true,
+	"TradesHistory":  true,
+	"TradeVolume":    true,
+	"WithdrawInfo":   true,
+	"WithdrawStatus": true,
+}
+
+// IsMutatingMethod returns true unless the private method is known to only read account data.
+// DepositAddresses can create addresses and GetWebSocketsToken grants websocket trading, so both count as mutating.
+func IsMutatingMethod(method string) bool {
+	return !readOnlyMethods[method]
+}
+
+// ReadOnly wraps api so that its private API refuses every mutating call with ErrReadOnly
+// before any request is sent. The public API is returned unchanged.
+func ReadOnly(api API) API {
+	return &readOnlyAPI{
+		public:  api.Public(),
+		private: &readOnlyPrivate{private: api.Private()},
+	}
+}
+
+type readOnlyAPI struct {
+	public  PublicAPI
+	private PrivateAPI
+}
+
+func (api *readOnlyAPI) Public() PublicAPI {
+	return api.public
+}
+
+func (api *readOnlyAPI) Private() PrivateAPI {
+	return api.private
+}
+
+// readOnlyPrivate implements every PrivateAPI method explicitly instead of embedding the
+// wrapped API, so a method added to PrivateAPI can't slip through without being classified.
+type readOnlyPrivate struct {
+	private PrivateAPI
+}
+
+func readOnlyError(method string) error {
+	return fmt.Errorf("%w: %s refused", ErrReadOnly, method)
+}
+
+func (api *readOnlyPrivate) TradesHistory(start int64, end int64, args map[string]string) (*TradesHistoryResponse, error) {
+	return api.private.TradesHistory(start, end, args)
+}
+
+func (api *readOnlyPrivate) Balance() (BalanceResponse, error) {
+	return api.private.Balance()
+}
+
+func (api *readOnlyPrivate) TradeBalance(args map[string]string) (*TradeBalanceResponse, error) {
+	return api.private.TradeBalance(args)
+}
+
+func (api *readOnlyPrivate) TradeVolume(args map[string]string) (*TradeVolumeResponse, error) {
+	return api.private.TradeVolume(args)
+}
+
+func (api *readOnlyPrivate) OpenOrders(args map[string]string) (*OpenOrdersResponse, error) {
+	return api.private.OpenOrders(args)
+}
+
+func (api *readOnlyPrivate) ClosedOrders(args map[string]string) (*ClosedOrdersResponse, error) {
+	return api.private.ClosedOrders(args)
+}
+
+func (api *readOnlyPrivate) CancelOrder(txid string) (*CancelOrderResponse, error) {
+	return nil, readOnlyError("CancelOrder")
+}
+
+func (api *readOnlyPrivate) QueryOrders(txids string, args map[string]string) (*QueryOrdersResponse, error) {
+	return api.private.QueryOrders(txids, args)
+}
+
+func (api *readOnlyPrivate) AddOrder(pair string, direction string, orderType string, volume string, args map[string]string) (*AddOrderResponse, error) {
+	return nil, readOnlyError("AddOrder")
+}
+
+func (api *readOnlyPrivate) Ledgers(args map[string]string) (*LedgersResponse, error) {
+	return api.private.Ledgers(args)
+}
+
+func (api *readOnlyPrivate) DepositAddresses(asset string, method string) (*DepositAddressesResponse, error) {
+	return nil, readOnlyError("DepositAddresses")
+}
+
+func (api *readOnlyPrivate) Withdraw(asset string, key string, amount *big.Float) (*WithdrawResponse, error) {
+	return nil, readOnlyError("Withdraw")
+}
+
+func (api *readOnlyPrivate) WithdrawInfo(asset string, key string, amount *big.Float) (*WithdrawInfoResponse, error) {
+	return api.private.WithdrawInfo(asset, key, amount)
+}
+
+func (api *readOnlyPrivate) DiscoverPermissions() (PermissionSet, error) {
+	return api.private.DiscoverPermissions()
+}
+
+func (api *readOnlyPrivate) Query(method string, args map[string]string) (interface{}, error) {
+	if IsMutatingMethod(method) {
+		return nil, readOnlyError(method)
+	}
+	return api.private.Query(method, args)
+}
@@ -0,0 +1,86 @@
+package krakenapi
+
+import (
+	"errors"
+	"math/big"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestReadOnly(t *testing.T) {
+	var called []string
+	api := ReadOnly(newTestAPI(func(method string, values url.Values) string {
+		called = append(called, method)
+		return `{"error":[],"result":{"ledger":{}}}`
+	}))
+
+	if _, err := api.Private().AddOrder(XXBTZEUR, "buy", OTMarket, "1", nil); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("AddOrder() should return ErrReadOnly, got %v", err)
+	}
+	if _, err := api.Private().CancelOrder("OABCDE-FGHIJ-KLMNOP"); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("CancelOrder() should return ErrReadOnly, got %v", err)
+	}
+	if _, err := api.Private().Withdraw("XXBT", "cold", big.NewFloat(1)); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Withdraw() should return ErrReadOnly, got %v", err)
+	}
+	for _, method := range []string{"WalletTransfer", "GetWebSocketsToken", "CancelAll", "EditOrder"} {
+		if _, err := api.Private().Query(method, nil); !errors.Is(err, ErrReadOnly) {
+			t.Errorf("Query(%s) should return ErrReadOnly, got %v", method, err)
+		}
+	}
+	if len(called) != 0 {
+		t.Errorf("Refused calls should not reach the network, got %v", called)
+	}
+
+	if _, err := api.Private().Ledgers(nil); err != nil {
+		t.Errorf("Ledgers() should not return an error, got %s", err)
+	}
+	if _, err := api.Private().Query("Ledgers", nil); err != nil {
+		t.Errorf("Query(Ledgers) should not return an error, got %s", err)
+	}
+	if len(called) != 2 {
+		t.Errorf("Read calls should reach the network, got %v", called)
+	}
+}
+
+func TestReadOnlyMatchesIsMutatingMethod(t *testing.T) {
+	private := ReadOnly(newTestAPI(func(method string, values url.Values) string {
+		return `{"error":[],"result":{}}`
+	})).Private()
+
+	calls := map[string]func() error{
+		"TradesHistory": func() error { _, err := private.TradesHistory(0, 0, nil); return err },
+		"Balance":       func() error { _, err := private.Balance(); return err },
+		"TradeBalance":  func() error { _, err := private.TradeBalance(nil); return err },
+		"TradeVolume":   func() error { _, err := private.TradeVolume(nil); return err },
+		"OpenOrders":    func() error { _, err := private.OpenOrders(nil); return err },
+		"ClosedOrders":  func() error { _, err := private.ClosedOrders(nil); return err },
+		"CancelOrder":   func() error { _, err := private.CancelOrder("OABCDE-FGHIJ-KLMNOP"); return err },
+		"QueryOrders":   func() error { _, err := private.QueryOrders("OABCDE-FGHIJ-KLMNOP", nil); return err },
+		"AddOrder":      func() error { _, err := private.AddOrder(XXBTZEUR, "buy", OTMarket, "1", nil); return err },
+		"Ledgers":       func() error { _, err := private.Ledgers(nil); return err },
+		"DepositAddresses": func() error {
+			_, err := private.DepositAddresses("XXBT", "Bitcoin")
+			return err
+		},
+		"Withdraw":     func() error { _, err := private.Withdraw("XXBT", "cold", big.NewFloat(1)); return err },
+		"WithdrawInfo": func() error { _, err := private.WithdrawInfo("XXBT", "cold", big.NewFloat(1)); return err },
+	}
+
+	// DiscoverPermissions only validates orders and Query is checked by method name
+	privateAPI := reflect.TypeOf((*PrivateAPI)(nil)).Elem()
+	for i := 0; i < privateAPI.NumMethod(); i++ {
+		name := privateAPI.Method(i).Name
+		if _, ok := calls[name]; !ok && name != "DiscoverPermissions" && name != "Query" {
+			t.Errorf("%s should be checked against IsMutatingMethod", name)
+		}
+	}
+
+	for method, call := range calls {
+		refused := errors.Is(call(), ErrReadOnly)
+		if refused != IsMutatingMethod(method) {
+			t.Errorf("%s should be refused %v like IsMutatingMethod, got %v", method, IsMutatingMethod(method), refused)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package krakenapi
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Resample combines candles of interval from into candles of interval to, which must be a multiple of from.
+// Candles must be in ascending time order as returned by OHLC. Resampled candles are aligned to the unix
+// epoch like Kraken's, the first and last ones only cover the given candles if those don't fill them.
+func Resample(candles []*OHLC, from, to Interval) ([]*OHLC, error) {
+	if from <= 0 || to <= 0 || to%from != 0 {
+		return nil, fmt.Errorf("Interval %s is not a multiple of %s", to, from)
+	}
+	if err := checkAscending(candles); err != nil {
+		return nil, err
+	}
+
+	var resampled []*OHLC
+	var current *OHLC
+	var cost float64
+	finish := func() {
+		if current == nil {
+			return
+		}
+		if current.Volume > 0 {
+			current.Vwap = cost / current.Volume
+		}
+		resampled = append(resampled, current)
+	}
+
+	for _, candle := range candles {
+		start := to.Align(candle.Time)
+		if current == nil || !current.Time.Equal(start) {
+			finish()
+			current = &OHLC{Time: start, Open: candle.Open, High: candle.High, Low: candle.Low}
+			cost = 0
+		}
+		if candle.High > current.High {
+			current.High = candle.High
+		}
+		if candle.Low < current.Low {
+			current.Low = candle.Low
+		}
+		current.Close = candle.Close
+		current.Volume += candle.Volume
+		current.Count += candle.Count
+		cost += candle.Vwap * candle.Volume
+		if current.Volume == 0 {
+			// Without volume there is nothing to weight, keep the last VWAP
+			current.Vwap = candle.Vwap
+		}
+	}
+	finish()
+
+	return resampled, nil
+}
+
+// OHLCGap is a run of missing candles in a series
+type OHLCGap struct {
+	// Time of the first missing candle
+	Start time.Time
+	// Number of missing candles
+	Count int
+}
+
+// FindGaps returns the missing candles between the first and last of candles, which must be in ascending time order
+func FindGaps(candles []*OHLC, interval Interval) []OHLCGap {
+	var gaps []OHLCGap
+	step := interval.Duration()
+	if step <= 0 {
+		return nil
+	}
+	for i := 1; i < len(candles); i++ {
+		expected := candles[i-1].Time.Add(step)
+		if missing := int(candles[i].Time.Sub(expected) / step); missing > 0 {
+			gaps = append(gaps, OHLCGap{Start: expected, Count: missing})
+		}
+	}
+	return gaps
+}
+
+// FillGaps returns candles with every gap filled by flat candles at the previous close, without volume or trades
+func FillGaps(candles []*OHLC, interval Interval) []*OHLC {
+	gaps := FindGaps(candles, interval)
+	if len(gaps) == 0 {
+		return candles
+	}
+
+	filled := make([]*OHLC, 0, len(candles))
+	step := interval.Duration()
+	for i, candle := range candles {
+		if i > 0 {
+			for t := candles[i-1].Time.Add(step); t.Before(candle.Time); t = t.Add(step) {
+				filled = append(filled, flatCandle(t, candles[i-1].Close))
+			}
+		}
+		filled = append(filled, candle)
+	}
+	return filled
+}
+
+// MergeOHLC merges series of the same pair and interval, for example from separate backfills, into one
+// series in ascending time order. When series overlap, the candle with more trades is kept and ties go
+// to the later series, so an unfinished candle is replaced by its complete version.
+func MergeOHLC(series ...[]*OHLC) []*OHLC {
+	byTime := make(map[int64]*OHLC)
+	for _, candles := range series {
+		for _, candle := range candles {
+			key := candle.Time.Unix()
+			if existing, ok := byTime[key]; ok && existing.Count > candle.Count {
+				continue
+			}
+			byTime[key] = candle
+		}
+	}
+
+	merged := make([]*OHLC, 0, len(byTime))
+	for _, candle := range byTime {
+		merged = append(merged, candle)
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Time.Before(merged[j].Time)
+	})
+	return merged
+}
+
+// checkAscending returns an error if candles are not in strictly ascending time order
+func checkAscending(candles []*OHLC) error {
+	for i := 1; i < len(candles); i++ {
+		if !candles[i].Time.After(candles[i-1].Time) {
+			return fmt.Errorf("candles are not in ascending order at %s", candles[i].Time)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package krakenapi
+
+import (
+	"testing"
+	"time"
+)
+
+func candleAt(minute int64, open, high, low, close, vwap, volume float64, count int) *OHLC {
+	return &OHLC{Time: time.Unix(minute*60, 0), Open: open, High: high, Low: low, Close: close, Vwap: vwap, Volume: volume, Count: count}
+}
+
+func TestResample(t *testing.T) {
+	candles := []*OHLC{
+		candleAt(0, 10, 12, 9, 11, 10.5, 2, 3),
+		candleAt(5, 11, 15, 10, 14, 13, 1, 1),
+		candleAt(10, 14, 14, 8, 9, 10, 1, 2),
+		candleAt(15, 9, 10, 9, 10, 9.5, 4, 5),
+	}
+
+	resampled, err := Resample(candles, Interval5m, Interval15m)
+	if err != nil {
+		t.Fatalf("Resample() should not return an error, got %s", err)
+	}
+	if len(resampled) != 2 {
+		t.Fatalf("Resample() should return 2 candles, got %d", len(resampled))
+	}
+	first := resampled[0]
+	if !first.Time.Equal(time.Unix(0, 0)) || first.Open != 10 || first.High != 15 || first.Low != 8 || first.Close != 9 {
+		t.Errorf("Resample() should combine open, high, low and close, got %+v", first)
+	}
+	if first.Volume != 4 || first.Count != 6 || !almostEqual(first.Vwap, (10.5*2+13+10)/4) {
+		t.Errorf("Resample() should combine volume, count and vwap, got %+v", first)
+	}
+	if !resampled[1].Time.Equal(time.Unix(15*60, 0)) || resampled[1].Vwap != 9.5 {
+		t.Errorf("Resample() should start a new candle at 15m, got %+v", resampled[1])
+	}
+
+	if _, err := Resample(candles, Interval5m, Interval(12)); err == nil {
+		t.Errorf("Resample() should fail if the interval is not a multiple")
+	}
+	if _, err := Resample([]*OHLC{candles[1], candles[0]}, Interval5m, Interval15m); err == nil {
+		t.Errorf("Resample() should fail if candles are not in order")
+	}
+}
+
+func TestFillGaps(t *testing.T) {
+	candles := []*OHLC{
+		candleAt(0, 10, 12, 9, 11, 10.5, 2, 3),
+		candleAt(1, 11, 12, 10, 12, 11, 1, 1),
+		candleAt(4, 12, 13, 12, 13, 12.5, 1, 1),
+	}
+
+	gaps := FindGaps(candles, Interval1m)
+	if len(gaps) != 1 || !gaps[0].Start.Equal(time.Unix(120, 0)) || gaps[0].Count != 2 {
+		t.Fatalf("FindGaps() should find 2 candles missing at 2m, got %+v", gaps)
+	}
+
+	filled := FillGaps(candles, Interval1m)
+	if len(filled) != 5 {
+		t.Fatalf("FillGaps() should return 5 candles, got %d", len(filled))
+	}
+	for _, candle := range filled[2:4] {
+		if candle.Open != 12 || candle.High != 12 || candle.Low != 12 || candle.Close != 12 || candle.Volume != 0 || candle.Count != 0 {
+			t.Errorf("FillGaps() should fill with flat candles at the previous close, got %+v", candle)
+		}
+	}
+	if !filled[3].Time.Equal(time.Unix(180, 0)) || filled[4] != candles[2] {
+		t.Errorf("FillGaps() should keep the series in order, got %+v", filled)
+	}
+}
+
+func TestMergeOHLC(t *testing.T) {
+	older := []*OHLC{
+		candleAt(0, 10, 12, 9, 11, 10.5, 2, 3),
+		candleAt(1, 11, 12, 10, 12, 11, 1, 1),
+	}
+	newer := []*OHLC{
+		candleAt(1, 11, 13, 10, 13, 12, 3, 4),
+		candleAt(2, 13, 13, 12, 12, 12.5, 1, 1),
+	}
+
+	merged := MergeOHLC(newer, older)
+	if len(merged) != 3 || merged[0] != older[0] || merged[2] != newer[1] {
+		t.Fatalf("MergeOHLC() should return the union in order, got %+v", merged)
+	}
+	if merged[1] != newer[0] {
+		t.Errorf("MergeOHLC() should keep the overlapping candle with more trades, got %+v", merged[1])
+	}
+}
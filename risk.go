@@ -0,0 +1,248 @@
+package krakenapi
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// RiskRule names a pre-trade check of the RiskGuard
+type RiskRule string
+
+// Pre-trade checks done by RiskGuard
+const (
+	RuleKillSwitch       RiskRule = "kill-switch"
+	RuleUnknownPair      RiskRule = "unknown-pair"
+	RuleInvalidVolume    RiskRule = "invalid-volume"
+	RuleOrderMin         RiskRule = "order-min"
+	RuleLotDecimals      RiskRule = "lot-decimals"
+	RuleInvalidPrice     RiskRule = "invalid-price"
+	RulePriceDecimals    RiskRule = "price-decimals"
+	RuleCostMin          RiskRule = "cost-min"
+	RulePriceBand        RiskRule = "price-band"
+	RuleMaxOrderNotional RiskRule = "max-order-notional"
+	RuleMaxPairNotional  RiskRule = "max-pair-notional"
+	RuleMaxOpenOrders    RiskRule = "max-open-orders"
+)
+
+// RiskError is returned when an order is rejected by the RiskGuard
+type RiskError struct {
+	Rule   RiskRule
+	Reason string
+}
+
+func (e *RiskError) Error() string {
+	return fmt.Sprintf("order rejected by %s rule: %s", e.Rule, e.Reason)
+}
+
+// RiskLimits configures the RiskGuard, zero values disable a check
+type RiskLimits struct {
+	// Maximum notional of a single order in quote currency
+	MaxOrderNotional float64
+	// Maximum notional of open orders plus the new order per pair, in quote currency
+	MaxPairNotional map[string]float64
+	// Maximum number of open orders
+	MaxOpenOrders int
+	// Maximum distance of the order price from the latest bid/ask, e.g. 0.05 for 5%
+	PriceBand float64
+}
+
+// RiskGuard wraps a PrivateAPI and checks every order before it is sent, whether it is
+// placed with AddOrder or with Query.
+type RiskGuard struct {
+	PrivateAPI
+	public PublicAPI
+	limits RiskLimits
+	killed int32
+
+	mu    sync.Mutex
+	pairs *PairResolver
+}
+
+// NewRiskGuard creates a RiskGuard for api's private API, using its public API for market data
+func NewRiskGuard(api API, limits RiskLimits) *RiskGuard {
+	return &RiskGuard{
+		PrivateAPI: api.Private(),
+		public:     api.Public(),
+		limits:     limits,
+	}
+}
+
+// SetKillSwitch enables or disables the kill switch, which rejects every new order while enabled
+func (g *RiskGuard) SetKillSwitch(enabled bool) {
+	var value int32
+	if enabled {
+		value = 1
+	}
+	atomic.StoreInt32(&g.killed, value)
+}
+
+// KillSwitch returns true if the kill switch is enabled
+func (g *RiskGuard) KillSwitch() bool {
+	return atomic.LoadInt32(&g.killed) == 1
+}
+
+// AddOrder checks the order against the risk limits and only sends it if every check passes
+func (g *RiskGuard) AddOrder(pair string, direction string, orderType string, volume string, args map[string]string) (*AddOrderResponse, error) {
+	if err := g.CheckOrder(pair, direction, orderType, volume, args); err != nil {
+		return nil, err
+	}
+	return g.PrivateAPI.AddOrder(pair, direction, orderType, volume, args)
+}
+
+// Query executes any private method by name, orders are checked like AddOrder
+func (g *RiskGuard) Query(method string, args map[string]string) (interface{}, error) {
+	if method == "AddOrder" {
+		if err := g.CheckOrder(args["pair"], args["type"], args["ordertype"], args["volume"], args); err != nil {
+			return nil, err
+		}
+	}
+	return g.PrivateAPI.Query(method, args)
+}
+
+// CheckOrder runs the pre-trade checks for an order without sending it
+func (g *RiskGuard) CheckOrder(pair string, direction string, orderType string, volume string, args map[string]string) error {
+	if g.KillSwitch() {
+		return &RiskError{RuleKillSwitch, "kill switch is enabled"}
+	}
+
+	name, info, err := g.assetPair(pair)
+	if err != nil {
+		return err
+	}
+
+	vol, err := strconv.ParseFloat(volume, 64)
+	if err != nil {
+		return &RiskError{RuleInvalidVolume, fmt.Sprintf("volume %q is not a positive number", volume)}
+	}
+	// Check the volume before asking for the ticker
+	if err := info.CheckOrder(vol, 0); err != nil {
+		return err
+	}
+
+	ticker, err := g.public.Ticker(name)
+	if err != nil {
+		return err
+	}
+	tick := ticker.GetPairTickerInfo(name)
+	if len(tick.Ask) == 0 || len(tick.Bid) == 0 {
+		return fmt.Errorf("no ticker for %s", pair)
+	}
+	ask, _ := strconv.ParseFloat(tick.Ask[0], 64)
+	bid, _ := strconv.ParseFloat(tick.Bid[0], 64)
+
+	// Market orders are valued at the price they would take, others at their limit price
+	price := ask
+	if direction == "sell" {
+		price = bid
+	}
+	if value, ok := args["price"]; ok && hasAbsolutePrice(orderType) {
+		price, err = strconv.ParseFloat(value, 64)
+		if err != nil || price <= 0 {
+			return &RiskError{RuleInvalidPrice, fmt.Sprintf("price %q is not a positive number", value)}
+		}
+		if g.limits.PriceBand > 0 {
+			low, high := bid*(1-g.limits.PriceBand), ask*(1+g.limits.PriceBand)
+			if price < low || price > high {
+				return &RiskError{RulePriceBand, fmt.Sprintf("price %v is outside of %v - %v (bid %v, ask %v, band %v%%)",
+					price, low, high, bid, ask, g.limits.PriceBand*100)}
+			}
+		}
+	}
+	if err := info.CheckOrder(vol, price); err != nil {
+		return err
+	}
+
+	notional := vol * price
+	if g.limits.MaxOrderNotional > 0 && notional > g.limits.MaxOrderNotional {
+		return &RiskError{RuleMaxOrderNotional, fmt.Sprintf("order notional %v is above the maximum of %v", notional, g.limits.MaxOrderNotional)}
+	}
+
+	maxPairNotional := g.pairLimit(pair, name)
+	if g.limits.MaxOpenOrders <= 0 && maxPairNotional <= 0 {
+		return nil
+	}
+
+	open, err := g.PrivateAPI.OpenOrders(nil)
+	if err != nil {
+		return err
+	}
+	if g.limits.MaxOpenOrders > 0 && len(open.Open) >= g.limits.MaxOpenOrders {
+		return &RiskError{RuleMaxOpenOrders, fmt.Sprintf("%d orders are open, maximum is %d", len(open.Open), g.limits.MaxOpenOrders)}
+	}
+	if maxPairNotional > 0 {
+		total := notional + openNotional(open.Open, g.pairs, name, (bid+ask)/2)
+		if total > maxPairNotional {
+			return &RiskError{RuleMaxPairNotional, fmt.Sprintf("%s notional including open orders would be %v, maximum is %v", pair, total, maxPairNotional)}
+		}
+	}
+
+	return nil
+}
+
+// assetPair returns the canonical name and info of pair, given in any form known to a PairResolver
+func (g *RiskGuard) assetPair(pair string) (string, AssetPairInfo, error) {
+	resolver, err := g.resolver()
+	if err != nil {
+		return "", AssetPairInfo{}, err
+	}
+	name, err := resolver.Resolve(pair)
+	if err != nil {
+		return "", AssetPairInfo{}, &RiskError{RuleUnknownPair, fmt.Sprintf("%s is not a known asset pair", pair)}
+	}
+	info, _ := resolver.Info(name)
+	return name, info, nil
+}
+
+// resolver returns the PairResolver of all asset pairs, loaded on first use
+func (g *RiskGuard) resolver() (*PairResolver, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.pairs == nil {
+		resolver, err := LoadPairResolver(g.public)
+		if err != nil {
+			return nil, err
+		}
+		g.pairs = resolver
+	}
+	return g.pairs, nil
+}
+
+// pairLimit returns the configured notional limit of the pair of canonical name, whichever form its key is given in
+func (g *RiskGuard) pairLimit(pair, name string) float64 {
+	if limit, ok := g.limits.MaxPairNotional[pair]; ok {
+		return limit
+	}
+	for key, limit := range g.limits.MaxPairNotional {
+		if resolved, err := g.pairs.Resolve(key); err == nil && resolved == name {
+			return limit
+		}
+	}
+	return 0
+}
+
+// hasAbsolutePrice returns false for order types whose price is an offset
+func hasAbsolutePrice(orderType string) bool {
+	return orderType != OTMarket && orderType != OTTrailingStop && orderType != OTTrailingStopLimit
+}
+
+// openNotional returns the remaining notional of open orders on the pair of canonical name, whichever form the orders name it in
+func openNotional(orders map[string]Order, resolver *PairResolver, name string, reference float64) float64 {
+	var total float64
+	for _, order := range orders {
+		// Kraken names the pair by altname, WithPairResolver by canonical name
+		if resolver.resolveOrKeep(order.Description.AssetPair) != name {
+			continue
+		}
+		volume, _ := strconv.ParseFloat(order.Volume, 64)
+		price, _ := strconv.ParseFloat(order.Description.PrimaryPrice, 64)
+		if price <= 0 || !hasAbsolutePrice(order.Description.OrderType) {
+			price = reference
+		}
+		total += math.Max(volume-order.VolumeExecuted, 0) * price
+	}
+	return total
+}
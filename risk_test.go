@@ -0,0 +1,114 @@
+package krakenapi
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestRiskGuard(t *testing.T) {
+	var added int
+	api := newTestAPI(func(method string, values url.Values) string {
+		switch method {
+		case "AssetPairs":
+			return `{"error":[],"result":{"XXBTZEUR":{"altname":"XBTEUR","base":"XXBT","quote":"ZEUR","lot_decimals":8,"pair_decimals":1,"ordermin":"0.0001"}}}`
+		case "Ticker":
+			return `{"error":[],"result":{"XXBTZEUR":{"a":["20010.0","1","1.000"],"b":["20000.0","1","1.000"],"c":["20005.0","0.1"],"o":"19900.0"}}}`
+		case "OpenOrders":
+			return `{"error":[],"result":{"open":{"OQCLML-BW3P3-BUCMWZ":{"descr":{"pair":"XBTEUR","type":"buy","ordertype":"limit","price":"19000.0"},"vol":"1.00000000","vol_exec":"0.50000000"}}}}`
+		case "AddOrder":
+			added++
+			return `{"error":[],"result":{"descr":{"order":"buy 0.10000000 XBTEUR @ limit 20000.0"},"txid":["OUF4EM-FRGI2-MQMWZD"]}}`
+		}
+		return `{"error":["EGeneral:Invalid arguments"]}`
+	})
+
+	guard := NewRiskGuard(api, RiskLimits{
+		MaxOrderNotional: 5000,
+		MaxPairNotional:  map[string]float64{"XBTEUR": 15000},
+		MaxOpenOrders:    2,
+		PriceBand:        0.05,
+	})
+
+	tests := []struct {
+		pair      string
+		volume    string
+		orderType string
+		price     string
+		rule      RiskRule
+	}{
+		{XXBTZEUR, "0.00001", OTLimit, "20000", RuleOrderMin},
+		{XXBTZEUR, "0.123456789", OTLimit, "20000", RuleLotDecimals},
+		{XXBTZEUR, "0.1", OTLimit, "20000.05", RulePriceDecimals},
+		{XXBTZEUR, "0.1", OTLimit, "2000", RulePriceBand},
+		{XXBTZEUR, "0.1", OTLimit, "200000", RulePriceBand},
+		{XXBTZEUR, "1", OTMarket, "", RuleMaxOrderNotional},
+		{"XBTUSD", "0.1", OTMarket, "", RuleUnknownPair},
+		{"XBTEUR", "0.1", OTLimit, "20000", ""},
+	}
+	for _, test := range tests {
+		args := map[string]string{}
+		if test.price != "" {
+			args["price"] = test.price
+		}
+		_, err := guard.AddOrder(test.pair, "buy", test.orderType, test.volume, args)
+		if test.rule == "" {
+			if err != nil {
+				t.Errorf("AddOrder(%s %s @ %s) should pass, got %s", test.volume, test.pair, test.price, err)
+			}
+			continue
+		}
+		if riskErr, ok := err.(*RiskError); !ok || riskErr.Rule != test.rule {
+			t.Errorf("AddOrder(%s %s @ %s) should be rejected by %s, got %v", test.volume, test.pair, test.price, test.rule, err)
+		}
+	}
+
+	// 0.5 XBT open at 19000 plus 0.24 XBT at 20000 is above the pair limit of 15000
+	guard.limits.MaxOrderNotional = 0
+	guard.limits.MaxPairNotional["XBTEUR"] = 14000
+	if _, err := guard.AddOrder(XXBTZEUR, "buy", OTLimit, "0.24", map[string]string{"price": "20000"}); err == nil {
+		t.Errorf("AddOrder() should be rejected by the pair notional limit")
+	}
+
+	guard.limits.MaxOpenOrders = 1
+	if err, ok := guard.CheckOrder(XXBTZEUR, "buy", OTLimit, "0.1", map[string]string{"price": "20000"}).(*RiskError); !ok || err.Rule != RuleMaxOpenOrders {
+		t.Errorf("CheckOrder() should be rejected by the open orders limit, got %v", err)
+	}
+
+	guard.SetKillSwitch(true)
+	if err, ok := guard.CheckOrder(XXBTZEUR, "buy", OTLimit, "0.1", map[string]string{"price": "20000"}).(*RiskError); !ok || err.Rule != RuleKillSwitch {
+		t.Errorf("CheckOrder() should be rejected by the kill switch, got %v", err)
+	}
+	query := map[string]string{"pair": XXBTZEUR, "type": "buy", "ordertype": OTLimit, "volume": "0.1", "price": "20000"}
+	if _, err := guard.Query("AddOrder", query); err == nil {
+		t.Errorf("Query(AddOrder) should be rejected by the kill switch")
+	}
+
+	if added != 1 {
+		t.Errorf("Only the accepted order should be sent, got %d", added)
+	}
+}
+
+func TestRiskGuardWithPairResolver(t *testing.T) {
+	api := newTestAPI(func(method string, values url.Values) string {
+		switch method {
+		case "AssetPairs":
+			return `{"error":[],"result":{"XXBTZEUR":{"altname":"XBTEUR","wsname":"XBT/EUR","base":"XXBT","quote":"ZEUR","lot_decimals":8,"pair_decimals":1}}}`
+		case "Ticker":
+			return `{"error":[],"result":{"XXBTZEUR":{"a":["20010.0","1","1.000"],"b":["20000.0","1","1.000"],"c":["20005.0","0.1"],"o":"19900.0"}}}`
+		case "OpenOrders":
+			return `{"error":[],"result":{"open":{"OQCLML-BW3P3-BUCMWZ":{"descr":{"pair":"XBTEUR","type":"buy","ordertype":"limit","price":"19000.0"},"vol":"1.00000000","vol_exec":"0.50000000"}}}}`
+		}
+		return `{"error":["EGeneral:Invalid arguments"]}`
+	})
+	r, err := LoadPairResolver(api.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Open orders come back named XXBTZEUR, their 9500 must still count against the limit
+	guard := NewRiskGuard(WithPairResolver(api, r), RiskLimits{MaxPairNotional: map[string]float64{"BTC/EUR": 12000}})
+	err = guard.CheckOrder("XBT/EUR", "buy", OTLimit, "0.2", map[string]string{"price": "20000"})
+	if riskErr, ok := err.(*RiskError); !ok || riskErr.Rule != RuleMaxPairNotional {
+		t.Errorf("CheckOrder() should count open orders named by canonical name, got %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package krakenapi
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultPollInterval is the time iterators wait before asking Kraken for new data once they caught up
+const DefaultPollInterval = 5 * time.Second
+
+// SpreadIterator keeps following the `last` cursor of the Spread endpoint to build a
+// continuous spread history. It never ends on its own, stop it by cancelling the context.
+//
+//	it := NewSpreadIterator(api.Public(), XXBTZEUR, 0)
+//	for it.Next(ctx) {
+//		spread := it.Spread()
+//	}
+//	if err := it.Err(); err != nil && err != context.Canceled {
+//		...
+//	}
+type SpreadIterator struct {
+	// Time to wait between two Spread calls
+	PollInterval time.Duration
+
+	api     PublicAPI
+	pair    string
+	last    int64
+	fetched bool
+	buffer  []SpreadItem
+	current SpreadItem
+	err     error
+}
+
+// NewSpreadIterator creates a SpreadIterator for pair starting at the since cursor, 0 for the most recent spreads
+func NewSpreadIterator(api PublicAPI, pair string, since int64) *SpreadIterator {
+	return &SpreadIterator{
+		PollInterval: DefaultPollInterval,
+		api:          api,
+		pair:         pair,
+		last:         since,
+	}
+}
+
+// Next advances to the next spread, waiting for new data if needed. It returns false
+// when the context is done or a request failed, see Err.
+func (it *SpreadIterator) Next(ctx context.Context) bool {
+	for len(it.buffer) == 0 {
+		if it.err != nil {
+			return false
+		}
+		if it.fetched {
+			if it.err = sleepContext(ctx, it.PollInterval); it.err != nil {
+				return false
+			}
+		}
+
+		resp, err := it.api.Spread(it.pair, it.last)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.fetched = true
+		it.last = resp.Last
+		it.buffer = resp.Spreads
+	}
+
+	it.current, it.buffer = it.buffer[0], it.buffer[1:]
+	return true
+}
+
+// Spread returns the current spread
+func (it *SpreadIterator) Spread() SpreadItem {
+	return it.current
+}
+
+// Last returns the cursor to resume from with NewSpreadIterator
+func (it *SpreadIterator) Last() int64 {
+	return it.last
+}
+
+// Err returns the error that stopped the iterator
+func (it *SpreadIterator) Err() error {
+	return it.err
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,45 @@
+package krakenapi
+
+import (
+	"context"
+	"net/url"
+	"testing"
+)
+
+func TestSpreadIterator(t *testing.T) {
+	var cursors []string
+	api := newTestAPI(func(method string, values url.Values) string {
+		cursors = append(cursors, values.Get("since"))
+		switch values.Get("since") {
+		case "":
+			return `{"error":[],"result":{"XXBTZEUR":[[1534614057,"6389.20000","6389.30000"],[1534614058,"6389.10000","6389.30000"]],"last":1534614058}}`
+		case "1534614058":
+			return `{"error":[],"result":{"XXBTZEUR":[[1534614060,"6389.00000","6389.40000"]],"last":1534614060}}`
+		}
+		return `{"error":[],"result":{"XXBTZEUR":[],"last":1534614060}}`
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	it := NewSpreadIterator(api.Public(), "XBTEUR", 0)
+	it.PollInterval = 0
+
+	var spreads []SpreadItem
+	for it.Next(ctx) {
+		spreads = append(spreads, it.Spread())
+		if len(spreads) == 3 {
+			cancel()
+		}
+	}
+
+	if it.Err() != context.Canceled {
+		t.Errorf("SpreadIterator should stop when cancelled, got %v", it.Err())
+	}
+	if len(spreads) != 3 || spreads[2].Bid.Float64() != 6389 || spreads[2].Ask.String() != "6389.40000" {
+		t.Errorf("SpreadIterator should return every spread, got %+v", spreads)
+	}
+	if it.Last() != 1534614060 || cursors[1] != "1534614058" {
+		t.Errorf("SpreadIterator should follow the last cursor, got %d and %v", it.Last(), cursors)
+	}
+}
@@ -0,0 +1,203 @@
+package krakenapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrTradingUnavailable is returned by the MaintenanceGuard for calls Kraken's system status doesn't allow
+var ErrTradingUnavailable = errors.New("krakenapi: trading unavailable")
+
+// SystemStatusChange is a system status transition seen by a StatusWatcher
+type SystemStatusChange struct {
+	From SystemStatus
+	To   SystemStatus
+	Time time.Time
+}
+
+// StatusWatcher polls the system status and emits its transitions
+type StatusWatcher struct {
+	api      PublicAPI
+	interval time.Duration
+	changes  chan SystemStatusChange
+	running  int32
+
+	mu      sync.RWMutex
+	status  SystemStatus
+	err     error
+	dropped int
+}
+
+// NewStatusWatcher creates a StatusWatcher polling api every interval
+func NewStatusWatcher(api PublicAPI, interval time.Duration) *StatusWatcher {
+	return &StatusWatcher{
+		api:      api,
+		interval: interval,
+		changes:  make(chan SystemStatusChange, 16),
+	}
+}
+
+// Run polls the system status until ctx is done, then closes the Changes channel. It may only be
+// called once. The first successful poll is emitted as a transition from the empty status.
+// Failed polls keep the last known status, see Err.
+func (w *StatusWatcher) Run(ctx context.Context) error {
+	if !atomic.CompareAndSwapInt32(&w.running, 0, 1) {
+		return errors.New("StatusWatcher is already running")
+	}
+	defer close(w.changes)
+
+	for {
+		w.poll()
+		if err := sleepContext(ctx, w.interval); err != nil {
+			return err
+		}
+	}
+}
+
+func (w *StatusWatcher) poll() {
+	resp, err := w.api.SystemStatus()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.err = err
+	if err != nil || resp.Status == w.status {
+		return
+	}
+
+	change := SystemStatusChange{From: w.status, To: resp.Status, Time: time.Now()}
+	w.status = resp.Status
+
+	// Status() stays current even if nobody drains the channel
+	select {
+	case w.changes <- change:
+	default:
+		w.dropped++
+	}
+}
+
+// Changes returns the channel status transitions are sent to, it is closed when Run returns.
+// Transitions are dropped while the channel's buffer is full, see Dropped.
+func (w *StatusWatcher) Changes() <-chan SystemStatusChange {
+	return w.changes
+}
+
+// Dropped returns the number of transitions that were not sent because the Changes buffer was full
+func (w *StatusWatcher) Dropped() int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.dropped
+}
+
+// Status returns the last known system status, empty before the first successful poll
+func (w *StatusWatcher) Status() SystemStatus {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.status
+}
+
+// Err returns the error of the last poll
+func (w *StatusWatcher) Err() error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.err
+}
+
+// MaintenanceGuard wraps a PrivateAPI and refuses calls the current system status doesn't allow.
+// While post_only only post-only limit orders are accepted, while cancel_only nothing
+// but CancelOrder and read calls, and during maintenance only read calls. Until the watcher
+// knows the status, only read calls are accepted.
+type MaintenanceGuard struct {
+	PrivateAPI
+	watcher *StatusWatcher
+}
+
+// NewMaintenanceGuard creates a MaintenanceGuard using the status of watcher, which must be running
+func NewMaintenanceGuard(api PrivateAPI, watcher *StatusWatcher) *MaintenanceGuard {
+	return &MaintenanceGuard{
+		PrivateAPI: api,
+		watcher:    watcher,
+	}
+}
+
+// AddOrder adds new order if the system status allows it
+func (g *MaintenanceGuard) AddOrder(pair string, direction string, orderType string, volume string, args map[string]string) (*AddOrderResponse, error) {
+	if err := g.checkOrder(orderType, args); err != nil {
+		return nil, err
+	}
+	return g.PrivateAPI.AddOrder(pair, direction, orderType, volume, args)
+}
+
+// CancelOrder cancels order if the system status allows it
+func (g *MaintenanceGuard) CancelOrder(txid string) (*CancelOrderResponse, error) {
+	if err := g.check("CancelOrder"); err != nil {
+		return nil, err
+	}
+	return g.PrivateAPI.CancelOrder(txid)
+}
+
+// Withdraw executes a withdrawal if the system status allows it
+func (g *MaintenanceGuard) Withdraw(asset string, key string, amount *big.Float) (*WithdrawResponse, error) {
+	if err := g.check("Withdraw"); err != nil {
+		return nil, err
+	}
+	return g.PrivateAPI.Withdraw(asset, key, amount)
+}
+
+// Query executes any private method by name if the system status allows it
+func (g *MaintenanceGuard) Query(method string, args map[string]string) (interface{}, error) {
+	var err error
+	if method == "AddOrder" {
+		err = g.checkOrder(args["ordertype"], args)
+	} else {
+		err = g.check(method)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return g.PrivateAPI.Query(method, args)
+}
+
+// checkOrder refuses orders that aren't post-only limit orders unless Kraken is online
+func (g *MaintenanceGuard) checkOrder(orderType string, args map[string]string) error {
+	status := g.watcher.Status()
+	if status != StatusPostOnly {
+		return g.check("AddOrder")
+	}
+	if orderType != OTLimit || !hasOrderFlag(args["oflags"], "post") {
+		return fmt.Errorf("%w: only post-only limit orders are accepted while %s", ErrTradingUnavailable, status)
+	}
+	return nil
+}
+
+// check refuses mutating methods the system status doesn't allow
+func (g *MaintenanceGuard) check(method string) error {
+	status := g.watcher.Status()
+	switch {
+	case !IsMutatingMethod(method):
+		return nil
+	case status == "":
+		return fmt.Errorf("%w: %s refused while the system status is unknown", ErrTradingUnavailable, method)
+	case status == StatusMaintenance,
+		status == StatusCancelOnly && method != "CancelOrder",
+		status == StatusPostOnly && method == "AddOrder":
+		return fmt.Errorf("%w: %s refused while %s", ErrTradingUnavailable, method, status)
+	}
+	return nil
+}
+
+// hasOrderFlag returns true if the comma separated oflags contain flag
+func hasOrderFlag(oflags string, flag string) bool {
+	for _, f := range strings.Split(oflags, ",") {
+		if strings.TrimSpace(f) == flag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package krakenapi
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMaintenanceGuard(t *testing.T) {
+	var status atomic.Value
+	status.Store(StatusPostOnly)
+	api := newTestAPI(func(method string, values url.Values) string {
+		switch method {
+		case "SystemStatus":
+			return `{"error":[],"result":{"status":"` + string(status.Load().(SystemStatus)) + `","timestamp":"2023-07-06T18:52:00Z"}}`
+		case "AddOrder":
+			return `{"error":[],"result":{"txid":["OUF4EM-FRGI2-MQMWZD"]}}`
+		}
+		return `{"error":[],"result":{"count":1}}`
+	})
+
+	watcher := NewStatusWatcher(api.Public(), time.Millisecond)
+	guard := NewMaintenanceGuard(api.Private(), watcher)
+
+	if _, err := guard.AddOrder(XXBTZEUR, "buy", OTLimit, "1", map[string]string{"price": "1", "oflags": "post"}); !errors.Is(err, ErrTradingUnavailable) {
+		t.Errorf("AddOrder() should be refused before the status is known, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error)
+	go func() {
+		done <- watcher.Run(ctx)
+	}()
+
+	if change := <-watcher.Changes(); change.From != "" || change.To != StatusPostOnly {
+		t.Fatalf("StatusWatcher should emit the initial status, got %+v", change)
+	}
+
+	if _, err := guard.AddOrder(XXBTZEUR, "buy", OTLimit, "1", map[string]string{"price": "1"}); !errors.Is(err, ErrTradingUnavailable) {
+		t.Errorf("AddOrder() should refuse orders without post flag while post_only, got %v", err)
+	}
+	if _, err := guard.AddOrder(XXBTZEUR, "buy", OTLimit, "1", map[string]string{"price": "1", "oflags": "fciq,post"}); err != nil {
+		t.Errorf("AddOrder() should accept post-only orders while post_only, got %v", err)
+	}
+
+	status.Store(StatusCancelOnly)
+	if change := <-watcher.Changes(); change.From != StatusPostOnly || change.To != StatusCancelOnly {
+		t.Fatalf("StatusWatcher should emit the transition, got %+v", change)
+	}
+
+	if _, err := guard.AddOrder(XXBTZEUR, "buy", OTLimit, "1", map[string]string{"price": "1", "oflags": "post"}); !errors.Is(err, ErrTradingUnavailable) {
+		t.Errorf("AddOrder() should be refused while cancel_only, got %v", err)
+	}
+	if _, err := guard.Query("AddOrder", map[string]string{"ordertype": OTLimit, "oflags": "post"}); !errors.Is(err, ErrTradingUnavailable) {
+		t.Errorf("Query(AddOrder) should be refused while cancel_only, got %v", err)
+	}
+	if _, err := guard.CancelOrder("OUF4EM-FRGI2-MQMWZD"); err != nil {
+		t.Errorf("CancelOrder() should be accepted while cancel_only, got %v", err)
+	}
+
+	cancel()
+	for range watcher.Changes() {
+	}
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() should return the context error, got %v", err)
+	}
+	if err := watcher.Run(context.Background()); err == nil {
+		t.Errorf("Run() should fail when called twice")
+	}
+}
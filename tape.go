@@ -0,0 +1,225 @@
+package krakenapi
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// minLargeTradeSample is the number of trades in the window below which LargePercentile detects nothing
+const minLargeTradeSample = 20
+
+// TradeTape aggregates the trades of a pair over a rolling time window. It is fed one trade at a time
+// from a TradesIterator or a websocket feed and can be snapshotted at any time. The window is measured
+// by trade time, it ends with the newest trade unless a snapshot is taken at a later time.
+// A TradeTape is safe for concurrent use, its thresholds must be set before it is fed.
+type TradeTape struct {
+	// Trades of at least this volume are large, 0 to disable
+	LargeVolume float64
+	// Trades above this percentile of the volumes in the window are large, e.g. 0.99, 0 to disable
+	LargePercentile float64
+
+	mu     sync.Mutex
+	window time.Duration
+	trades []tapeTrade
+	// Volumes of the trades in the window, sorted
+	volumes []float64
+	newest  int64
+}
+
+// tapeTrade is a trade in the window of a TradeTape
+type tapeTrade struct {
+	trade TradeInfo
+	large bool
+}
+
+// NewTradeTape creates a TradeTape aggregating the trades of the last window
+func NewTradeTape(window time.Duration) *TradeTape {
+	return &TradeTape{window: window}
+}
+
+// Add adds a trade and returns true if it is large
+func (t *TradeTape) Add(trade TradeInfo) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.add(trade)
+}
+
+// AddTrades adds trades, as returned in TradesResponse, and returns the large ones
+func (t *TradeTape) AddTrades(trades []TradeInfo) []TradeInfo {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var large []TradeInfo
+	for _, trade := range trades {
+		if t.add(trade) {
+			large = append(large, trade)
+		}
+	}
+	return large
+}
+
+func (t *TradeTape) add(trade TradeInfo) bool {
+	large := t.isLarge(trade)
+
+	// Trades normally arrive in order, keep the window sorted if they don't
+	i := len(t.trades)
+	if i > 0 && trade.Time < t.trades[i-1].trade.Time {
+		i = sort.Search(len(t.trades), func(j int) bool { return t.trades[j].trade.Time > trade.Time })
+	}
+	t.trades = append(t.trades, tapeTrade{})
+	copy(t.trades[i+1:], t.trades[i:])
+	t.trades[i] = tapeTrade{trade: trade, large: large}
+	t.insertVolume(trade.VolumeFloat)
+
+	if trade.Time > t.newest {
+		t.newest = trade.Time
+	}
+	start := t.windowStart(t.newest)
+	for _, old := range t.trades[:start] {
+		t.removeVolume(old.trade.VolumeFloat)
+	}
+	t.trades = t.trades[start:]
+	return large
+}
+
+// insertVolume adds volume to the sorted volumes of the window
+func (t *TradeTape) insertVolume(volume float64) {
+	i := sort.SearchFloat64s(t.volumes, volume)
+	t.volumes = append(t.volumes, 0)
+	copy(t.volumes[i+1:], t.volumes[i:])
+	t.volumes[i] = volume
+}
+
+// removeVolume removes volume from the sorted volumes of the window
+func (t *TradeTape) removeVolume(volume float64) {
+	i := sort.SearchFloat64s(t.volumes, volume)
+	if i < len(t.volumes) && t.volumes[i] == volume {
+		t.volumes = append(t.volumes[:i], t.volumes[i+1:]...)
+	}
+}
+
+// isLarge returns true if trade is large compared to the thresholds and the trades in the window
+func (t *TradeTape) isLarge(trade TradeInfo) bool {
+	if t.LargeVolume > 0 && trade.VolumeFloat >= t.LargeVolume {
+		return true
+	}
+	if t.LargePercentile > 0 && len(t.volumes) >= minLargeTradeSample {
+		return trade.VolumeFloat > percentile(t.volumes, t.LargePercentile)
+	}
+	return false
+}
+
+// windowStart returns the index of the first trade in the window ending at end
+func (t *TradeTape) windowStart(end int64) int {
+	start := end - int64(t.window/time.Second)
+	return sort.Search(len(t.trades), func(i int) bool { return t.trades[i].trade.Time > start })
+}
+
+// Snapshot returns the metrics of the window ending at the newest trade, or at given time if it is later.
+// Pass the zero time to snapshot the window of the newest trade.
+func (t *TradeTape) Snapshot(at time.Time) *TapeSnapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	end := t.newest
+	if at.Unix() > end {
+		end = at.Unix()
+	}
+
+	s := &TapeSnapshot{
+		Start: time.Unix(end, 0).Add(-t.window),
+		End:   time.Unix(end, 0),
+	}
+	for _, entry := range t.trades[t.windowStart(end):] {
+		trade := entry.trade
+		s.Trades++
+		s.Volume += trade.VolumeFloat
+		s.Cost += trade.VolumeFloat * trade.PriceFloat
+		if trade.Buy {
+			s.BuyVolume += trade.VolumeFloat
+		}
+		if trade.Sell {
+			s.SellVolume += trade.VolumeFloat
+		}
+		if trade.Market {
+			s.MarketVolume += trade.VolumeFloat
+		}
+		if trade.Limit {
+			s.LimitVolume += trade.VolumeFloat
+		}
+		if entry.large {
+			s.LargeTrades = append(s.LargeTrades, trade)
+		}
+		s.volumes = append(s.volumes, trade.VolumeFloat)
+	}
+	sort.Float64s(s.volumes)
+	return s
+}
+
+// TapeSnapshot holds the metrics of the trades in a window of a TradeTape
+type TapeSnapshot struct {
+	// Window covered by the snapshot, Start excluded
+	Start time.Time
+	End   time.Time
+	// Number of trades
+	Trades int
+	// Base volume traded, in total and by taker side and order type
+	Volume       float64
+	BuyVolume    float64
+	SellVolume   float64
+	MarketVolume float64
+	LimitVolume  float64
+	// Quote volume traded
+	Cost float64
+	// Large trades, oldest first
+	LargeTrades []TradeInfo
+
+	volumes []float64
+}
+
+// VWAP returns the volume weighted average price, 0 without volume
+func (s *TapeSnapshot) VWAP() float64 {
+	if s.Volume == 0 {
+		return 0
+	}
+	return s.Cost / s.Volume
+}
+
+// Imbalance returns (buy volume - sell volume) / (buy volume + sell volume), between -1 and 1
+func (s *TapeSnapshot) Imbalance() float64 {
+	total := s.BuyVolume + s.SellVolume
+	if total == 0 {
+		return 0
+	}
+	return (s.BuyVolume - s.SellVolume) / total
+}
+
+// MarketShare returns the share of the volume traded by market orders, between 0 and 1
+func (s *TapeSnapshot) MarketShare() float64 {
+	total := s.MarketVolume + s.LimitVolume
+	if total == 0 {
+		return 0
+	}
+	return s.MarketVolume / total
+}
+
+// SizePercentile returns the p-th percentile of the trade volumes, p between 0 and 1, 0 without trades
+func (s *TapeSnapshot) SizePercentile(p float64) float64 {
+	return percentile(s.volumes, p)
+}
+
+// percentile returns the p-th percentile of sorted values, interpolating linearly between ranks
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	p = math.Max(0, math.Min(1, p))
+	rank := p * float64(len(sorted)-1)
+	low := int(rank)
+	if low+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[low] + (rank-float64(low))*(sorted[low+1]-sorted[low])
+}
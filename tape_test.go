@@ -0,0 +1,120 @@
+package krakenapi
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTradeTape(t *testing.T) {
+	tape := NewTradeTape(time.Minute)
+	tape.LargeVolume = 5
+
+	buy := func(ts int64, price, volume float64) TradeInfo {
+		trade := trade(ts, price, volume)
+		trade.Buy, trade.Market = true, true
+		return trade
+	}
+	sell := func(ts int64, price, volume float64) TradeInfo {
+		trade := trade(ts, price, volume)
+		trade.Sell, trade.Limit = true, true
+		return trade
+	}
+
+	large := tape.AddTrades([]TradeInfo{
+		buy(0, 100, 10),
+		buy(30, 100, 1),
+		sell(40, 102, 3),
+	})
+	if len(large) != 1 || large[0].VolumeFloat != 10 {
+		t.Errorf("AddTrades() should return the trade above LargeVolume, got %+v", large)
+	}
+	// Arrives out of order and pushes the first trade out of the window
+	tape.Add(buy(70, 101, 2))
+	tape.Add(sell(50, 99, 2))
+
+	s := tape.Snapshot(time.Time{})
+	if s.Trades != 4 || s.Volume != 8 || !s.End.Equal(time.Unix(70, 0)) {
+		t.Fatalf("Snapshot() should cover the last minute of trades, got %+v", s)
+	}
+	if !almostEqual(s.VWAP(), (100+3*102+2*101+2*99)/8.0) {
+		t.Errorf("VWAP() should return 100.75, got %v", s.VWAP())
+	}
+	if !almostEqual(s.Imbalance(), (3-5)/8.0) {
+		t.Errorf("Imbalance() should return -0.25, got %v", s.Imbalance())
+	}
+	if !almostEqual(s.MarketShare(), 3/8.0) {
+		t.Errorf("MarketShare() should return 0.375, got %v", s.MarketShare())
+	}
+	if s.SizePercentile(0.5) != 2 || s.SizePercentile(1) != 3 || !almostEqual(s.SizePercentile(0.25), 1.75) {
+		t.Errorf("SizePercentile() should interpolate between volumes 1, 2, 2 and 3")
+	}
+	if len(s.LargeTrades) != 0 {
+		t.Errorf("Snapshot() should drop large trades that left the window, got %+v", s.LargeTrades)
+	}
+
+	if later := tape.Snapshot(time.Unix(125, 0)); later.Trades != 1 {
+		t.Errorf("Snapshot() at a later time should only cover the trades of its window, got %d", later.Trades)
+	}
+}
+
+func TestTradeTapeLargePercentile(t *testing.T) {
+	tape := NewTradeTape(time.Hour)
+	tape.LargePercentile = 0.9
+
+	for i := 0; i < minLargeTradeSample-1; i++ {
+		if tape.Add(trade(int64(i), 100, float64(1+i%5))) {
+			t.Fatalf("Add() should not detect large trades before the sample is big enough")
+		}
+	}
+	tape.Add(trade(100, 100, 1))
+	if tape.Add(trade(101, 100, 5)) {
+		t.Errorf("Add() should not flag a trade at the percentile")
+	}
+	if !tape.Add(trade(102, 100, 50)) {
+		t.Errorf("Add() should flag a trade above the percentile")
+	}
+	if s := tape.Snapshot(time.Time{}); len(s.LargeTrades) != 1 || s.LargeTrades[0].VolumeFloat != 50 {
+		t.Errorf("Snapshot() should list the large trade, got %+v", s.LargeTrades)
+	}
+}
+
+func TestTradeTapeExpiredVolumes(t *testing.T) {
+	tape := NewTradeTape(30 * time.Second)
+	tape.LargePercentile = 0.5
+
+	for i := 0; i < minLargeTradeSample; i++ {
+		tape.Add(trade(int64(i), 100, 100))
+	}
+	for i := 0; i <= minLargeTradeSample; i++ {
+		tape.Add(trade(int64(100+i), 100, 1))
+	}
+	if len(tape.volumes) != len(tape.trades) {
+		t.Fatalf("Add() should drop the volumes of expired trades, got %d volumes for %d trades", len(tape.volumes), len(tape.trades))
+	}
+	if !tape.Add(trade(121, 100, 2)) {
+		t.Errorf("Add() should compare trades to the volumes of the window only")
+	}
+}
+
+func TestTradeTapeConcurrent(t *testing.T) {
+	tape := NewTradeTape(time.Minute)
+	tape.LargePercentile = 0.9
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				tape.Add(trade(int64(j), 100, float64(i+j%7)))
+				tape.Snapshot(time.Time{}).SizePercentile(0.5)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if s := tape.Snapshot(time.Time{}); s.Trades != len(tape.volumes) {
+		t.Errorf("Snapshot() should cover every trade of the window, got %d trades for %d volumes", s.Trades, len(tape.volumes))
+	}
+}
@@ -0,0 +1,193 @@
+package krakenapi
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// TickerLevel is the best ask or bid of a ticker
+type TickerLevel struct {
+	Price          Decimal
+	WholeLotVolume Decimal
+	LotVolume      Decimal
+}
+
+// TickerTrade is the last trade of a ticker
+type TickerTrade struct {
+	Price     Decimal
+	LotVolume Decimal
+}
+
+// TickerPeriod holds a ticker value for today and the last 24 hours
+type TickerPeriod struct {
+	Today   Decimal
+	Last24h Decimal
+}
+
+// TickerCount holds a ticker count for today and the last 24 hours
+type TickerCount struct {
+	Today   int
+	Last24h int
+}
+
+// TickerValues is the parsed form of PairTickerInfo
+type TickerValues struct {
+	Ask                TickerLevel
+	Bid                TickerLevel
+	Close              TickerTrade
+	Volume             TickerPeriod
+	VolumeAveragePrice TickerPeriod
+	Trades             TickerCount
+	Low                TickerPeriod
+	High               TickerPeriod
+	OpeningPrice       Decimal
+}
+
+// Parsed returns the ticker information with named, parsed values
+func (t PairTickerInfo) Parsed() (*TickerValues, error) {
+	v := &TickerValues{OpeningPrice: DecimalFromFloat(t.OpeningPrice)}
+	// Tickers decoded from Kraken keep the exact text, only tickers built in code go through float64
+	if t.openingPrice != "" {
+		price, err := ParseDecimal(t.openingPrice)
+		if err != nil {
+			return nil, fmt.Errorf("ticker opening price: %s", err)
+		}
+		v.OpeningPrice = price
+	}
+
+	fields := []struct {
+		name   string
+		values []string
+		dest   []*Decimal
+	}{
+		{"ask", t.Ask, []*Decimal{&v.Ask.Price, &v.Ask.WholeLotVolume, &v.Ask.LotVolume}},
+		{"bid", t.Bid, []*Decimal{&v.Bid.Price, &v.Bid.WholeLotVolume, &v.Bid.LotVolume}},
+		{"close", t.Close, []*Decimal{&v.Close.Price, &v.Close.LotVolume}},
+		{"volume", t.Volume, []*Decimal{&v.Volume.Today, &v.Volume.Last24h}},
+		{"volume average price", t.VolumeAveragePrice, []*Decimal{&v.VolumeAveragePrice.Today, &v.VolumeAveragePrice.Last24h}},
+		{"low", t.Low, []*Decimal{&v.Low.Today, &v.Low.Last24h}},
+		{"high", t.High, []*Decimal{&v.High.Today, &v.High.Last24h}},
+	}
+	for _, field := range fields {
+		if len(field.values) < len(field.dest) {
+			return nil, fmt.Errorf("ticker %s should have %d values, got %d", field.name, len(field.dest), len(field.values))
+		}
+		for i, dest := range field.dest {
+			value, err := ParseDecimal(field.values[i])
+			if err != nil {
+				return nil, fmt.Errorf("ticker %s: %s", field.name, err)
+			}
+			*dest = value
+		}
+	}
+
+	if len(t.Trades) < 2 {
+		return nil, fmt.Errorf("ticker trades should have 2 values, got %d", len(t.Trades))
+	}
+	v.Trades = TickerCount{Today: t.Trades[0], Last24h: t.Trades[1]}
+
+	return v, nil
+}
+
+// Mid returns the price between best ask and best bid
+func (v *TickerValues) Mid() float64 {
+	return (v.Ask.Price.Float64() + v.Bid.Price.Float64()) / 2
+}
+
+// Spread returns the difference between best ask and best bid
+func (v *TickerValues) Spread() float64 {
+	return v.Ask.Price.Float64() - v.Bid.Price.Float64()
+}
+
+// SpreadBps returns the spread in basis points of the mid price
+func (v *TickerValues) SpreadBps() float64 {
+	mid := v.Mid()
+	if mid == 0 {
+		return 0
+	}
+	return v.Spread() / mid * 10000
+}
+
+// MarketSnapshot holds the parsed tickers of many pairs at a point in time
+type MarketSnapshot struct {
+	Time    time.Time
+	Tickers map[string]*TickerValues
+	// Errors holds the pairs whose ticker could not be parsed, they are missing from Tickers
+	Errors map[string]error
+}
+
+// NewMarketSnapshot parses every ticker of the response into a MarketSnapshot taken at given time.
+// Pairs whose ticker can't be parsed are skipped and listed in Errors, an error is only returned
+// if no ticker could be parsed.
+func NewMarketSnapshot(tickers TickerResponse, at time.Time) (*MarketSnapshot, error) {
+	snapshot := &MarketSnapshot{
+		Time:    at,
+		Tickers: make(map[string]*TickerValues),
+		Errors:  make(map[string]error),
+	}
+	pairs := tickers.GetPairs()
+	for _, pair := range pairs {
+		values, err := tickers.GetPairTickerInfo(pair).Parsed()
+		if err != nil {
+			snapshot.Errors[pair] = err
+			continue
+		}
+		snapshot.Tickers[pair] = values
+	}
+	if len(pairs) > 0 && len(snapshot.Tickers) == 0 {
+		return nil, fmt.Errorf("no ticker could be parsed, %s: %s", pairs[0], snapshot.Errors[pairs[0]])
+	}
+	return snapshot, nil
+}
+
+// QuoteVolume returns the last 24 hours volume of pair in quote currency
+func (s *MarketSnapshot) QuoteVolume(pair string) float64 {
+	v, ok := s.Tickers[pair]
+	if !ok {
+		return 0
+	}
+	return v.Volume.Last24h.Float64() * v.VolumeAveragePrice.Last24h.Float64()
+}
+
+// ChangeToday returns the relative change of pair's last trade price since today's opening price.
+// Kraken's ticker opens at 00:00 UTC and has no price from 24 hours ago, use OHLC for a rolling 24h change.
+func (s *MarketSnapshot) ChangeToday(pair string) float64 {
+	v, ok := s.Tickers[pair]
+	if !ok || v.OpeningPrice.Float64() == 0 {
+		return 0
+	}
+	return (v.Close.Price.Float64() - v.OpeningPrice.Float64()) / v.OpeningPrice.Float64()
+}
+
+// RankByVolume returns the pairs ordered by descending quote volume of the last 24 hours.
+// Volumes of pairs with different quote currencies are compared as they are.
+func (s *MarketSnapshot) RankByVolume() []string {
+	return s.rank(func(pair string) float64 { return -s.QuoteVolume(pair) })
+}
+
+// RankBySpread returns the pairs ordered by ascending spread in basis points
+func (s *MarketSnapshot) RankBySpread() []string {
+	return s.rank(func(pair string) float64 { return s.Tickers[pair].SpreadBps() })
+}
+
+// RankByChangeToday returns the pairs ordered by descending change since today's opening price
+func (s *MarketSnapshot) RankByChangeToday() []string {
+	return s.rank(func(pair string) float64 { return -s.ChangeToday(pair) })
+}
+
+// rank returns the pairs ordered by ascending key, ties ordered by name
+func (s *MarketSnapshot) rank(key func(pair string) float64) []string {
+	pairs := make([]string, 0, len(s.Tickers))
+	for pair := range s.Tickers {
+		pairs = append(pairs, pair)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		ki, kj := key(pairs[i]), key(pairs[j])
+		if ki != kj {
+			return ki < kj
+		}
+		return pairs[i] < pairs[j]
+	})
+	return pairs
+}
@@ -0,0 +1,78 @@
+package krakenapi
+
+import (
+	"encoding/json"
+	"math"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestPairTickerInfoParsed(t *testing.T) {
+	var info PairTickerInfo
+	err := json.Unmarshal([]byte(`{"a":["30300.10000","1","1.000"],"b":["30300.00000","2","2.000"],"c":["30303.20000","0.00067643"],`+
+		`"v":["4083.67001100","4412.73601799"],"p":["30706.77771","30689.13205"],"t":[34619,38907],`+
+		`"l":["29868.30000","29868.30000"],"h":["31631.00000","31631.00000"],"o":"30502.80000"}`), &info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := info.Parsed()
+	if err != nil {
+		t.Fatalf("Parsed() should not return an error, got %s", err)
+	}
+
+	if v.Ask.Price.Float64() != 30300.1 || v.Bid.WholeLotVolume.Float64() != 2 || v.Close.LotVolume.Float64() != 0.00067643 {
+		t.Errorf("Parsed() should return ask, bid and close, got %+v %+v %+v", v.Ask, v.Bid, v.Close)
+	}
+	if v.Ask.Price.String() != "30300.10000" || v.Volume.Today.String() != "4083.67001100" {
+		t.Errorf("Parsed() should keep the exact decimals sent by Kraken, got %s %s", v.Ask.Price, v.Volume.Today)
+	}
+	if v.Volume.Today.Float64() != 4083.67001100 || v.Volume.Last24h.Float64() != 4412.73601799 || v.Trades.Last24h != 38907 {
+		t.Errorf("Parsed() should return today and last 24h values, got %+v %+v", v.Volume, v.Trades)
+	}
+	if v.High.Today.Float64() != 31631 || v.Low.Last24h.Float64() != 29868.3 || v.OpeningPrice.String() != "30502.80000" || info.OpeningPrice != 30502.8 {
+		t.Errorf("Parsed() should return high, low and opening price, got %+v %+v %v", v.High, v.Low, v.OpeningPrice)
+	}
+	if v.Mid() != 30300.05 || math.Abs(v.SpreadBps()-0.033) > 0.001 {
+		t.Errorf("Mid() and SpreadBps() should be computed from ask and bid, got %v %v", v.Mid(), v.SpreadBps())
+	}
+
+	if _, err := (PairTickerInfo{Ask: []string{"1"}}).Parsed(); err == nil {
+		t.Errorf("Parsed() should fail for incomplete ticker")
+	}
+}
+
+func TestMarketSnapshot(t *testing.T) {
+	api := newTestAPI(func(method string, values url.Values) string {
+		if _, ok := values["pair"]; ok {
+			t.Errorf("Ticker() without pairs should not send the pair parameter, got %v", values)
+		}
+		return `{"error":[],"result":{` +
+			`"XXBTZEUR":{"a":["101","1","1"],"b":["99","1","1"],"c":["100","1"],"v":["10","20"],"p":["100","100"],"t":[1,2],"l":["90","90"],"h":["110","110"],"o":"80"},` +
+			`"XETHZEUR":{"a":["10.01","1","1"],"b":["10","1","1"],"c":["10","1"],"v":["100","300"],"p":["10","10"],"t":[1,2],"l":["9","9"],"h":["11","11"],"o":"10"},` +
+			`"XXRPZEUR":{"a":["0.52","1","1"],"b":["0.5","1","1"],"c":["0.5","1"],"v":["1000","1000"],"p":["0.5","0.5"],"t":[1,2],"l":["0.4","0.4"],"h":["0.6","0.6"],"o":"1"},` +
+			`"XLTCZEUR":{"a":["x","1","1"],"b":["70","1","1"],"c":["70","1"],"v":["1","1"],"p":["70","70"],"t":[1,2],"l":["69","69"],"h":["71","71"],"o":"70"}}}`
+	})
+
+	snapshot, err := api.Public().MarketSnapshot()
+	if err != nil {
+		t.Fatalf("MarketSnapshot() should not return an error, got %s", err)
+	}
+
+	if snapshot.Time.IsZero() || len(snapshot.Tickers) != 3 {
+		t.Errorf("MarketSnapshot() should return every pair with a timestamp, got %+v", snapshot)
+	}
+	if len(snapshot.Errors) != 1 || snapshot.Errors[XLTCZEUR] == nil {
+		t.Errorf("MarketSnapshot() should skip and report unparsable tickers, got %v", snapshot.Errors)
+	}
+	if rank := snapshot.RankByVolume(); !reflect.DeepEqual(rank, []string{XETHZEUR, XXBTZEUR, XXRPZEUR}) {
+		t.Errorf("RankByVolume() should order by quote volume, got %v", rank)
+	}
+	if rank := snapshot.RankBySpread(); !reflect.DeepEqual(rank, []string{XETHZEUR, XXBTZEUR, XXRPZEUR}) {
+		t.Errorf("RankBySpread() should order by spread, got %v", rank)
+	}
+	if rank := snapshot.RankByChangeToday(); !reflect.DeepEqual(rank, []string{XXBTZEUR, XETHZEUR, XXRPZEUR}) {
+		t.Errorf("RankByChangeToday() should order by change, got %v", rank)
+	}
+}
@@ -0,0 +1,112 @@
+package krakenapi
+
+import (
+	"context"
+	"time"
+)
+
+// tradesPageSize is the maximum number of trades Kraken returns per Trades call
+const tradesPageSize = 1000
+
+// TradesIterator pages through the trades of a pair from a start cursor until it caught up with
+// real time, then stops or, if Follow is set, keeps polling for new trades.
+//
+//	it := NewTradesIterator(api.Public(), XXBTZEUR, cursor)
+//	for it.Next(ctx) {
+//		trade := it.Trade()
+//		cursor = it.Cursor()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type TradesIterator struct {
+	// Keep polling for new trades once caught up
+	Follow bool
+	// Time to wait between two Trades calls once caught up
+	PollInterval time.Duration
+
+	api      PublicAPI
+	pair     string
+	since    int64
+	last     int64
+	buffer   []TradeInfo
+	current  TradeInfo
+	caughtUp bool
+	err      error
+}
+
+// NewTradesIterator creates a TradesIterator for pair starting at the since cursor, 0 for the most recent trades
+func NewTradesIterator(api PublicAPI, pair string, since int64) *TradesIterator {
+	return &TradesIterator{
+		PollInterval: DefaultPollInterval,
+		api:          api,
+		pair:         pair,
+		since:        since,
+		last:         since,
+	}
+}
+
+// Next advances to the next trade. It returns false once caught up unless Follow is set,
+// when the context is done or when a request failed, see Err.
+func (it *TradesIterator) Next(ctx context.Context) bool {
+	for len(it.buffer) == 0 {
+		// The page is consumed, resume after it from now on
+		it.since = it.last
+		if it.err != nil {
+			return false
+		}
+		if it.err = ctx.Err(); it.err != nil {
+			return false
+		}
+		if it.caughtUp {
+			if !it.Follow {
+				return false
+			}
+			if it.err = sleepContext(ctx, it.PollInterval); it.err != nil {
+				return false
+			}
+		}
+		it.fetch()
+	}
+
+	it.current, it.buffer = it.buffer[0], it.buffer[1:]
+	return true
+}
+
+// fetch loads the next page of trades into the buffer
+func (it *TradesIterator) fetch() {
+	resp, err := it.api.Trades(it.pair, it.since)
+	if err != nil {
+		it.err = err
+		return
+	}
+
+	// Kraken answers with an unchanged cursor when there are no new trades
+	if resp.Last == it.since && it.since != 0 {
+		it.caughtUp = true
+		return
+	}
+
+	it.buffer = resp.Trades
+	it.last = resp.Last
+	it.caughtUp = len(resp.Trades) < tradesPageSize
+}
+
+// Trade returns the current trade
+func (it *TradesIterator) Trade() TradeInfo {
+	return it.current
+}
+
+// Cursor returns the cursor to resume from with NewTradesIterator. Until the current page is
+// consumed it points at the page's start, so resuming may repeat some trades but never skips one.
+func (it *TradesIterator) Cursor() int64 {
+	if len(it.buffer) == 0 {
+		return it.last
+	}
+	return it.since
+}
+
+// Err returns the error that stopped the iterator
+func (it *TradesIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,65 @@
+package krakenapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// tradesPage returns a Trades response holding count trades and the last cursor
+func tradesPage(count int, last int64) string {
+	trades := make([]string, count)
+	for i := range trades {
+		trades[i] = fmt.Sprintf(`["20000.%d","0.1",%d.1234,"b","l",""]`, i, 1688671968+i)
+	}
+	return fmt.Sprintf(`{"error":[],"result":{"XXBTZEUR":[%s],"last":"%d"}}`, strings.Join(trades, ","), last)
+}
+
+func TestTradesIterator(t *testing.T) {
+	var calls []string
+	api := newTestAPI(func(method string, values url.Values) string {
+		calls = append(calls, values.Get("since"))
+		switch values.Get("since") {
+		case "100":
+			return tradesPage(tradesPageSize, 200)
+		case "200":
+			return tradesPage(3, 300)
+		}
+		return tradesPage(0, 300)
+	})
+
+	it := NewTradesIterator(api.Public(), "XBTEUR", 100)
+	var count int
+	for it.Next(context.Background()) {
+		count++
+		if count == 1 && it.Cursor() != 100 {
+			t.Errorf("Cursor() should point at the page start while consuming it, got %d", it.Cursor())
+		}
+	}
+	if it.Err() != nil {
+		t.Errorf("TradesIterator should not return an error, got %s", it.Err())
+	}
+	if count != tradesPageSize+3 || it.Cursor() != 300 {
+		t.Errorf("TradesIterator should return every trade up to now, got %d trades and cursor %d", count, it.Cursor())
+	}
+	if len(calls) != 2 {
+		t.Errorf("TradesIterator should stop once caught up, got calls %v", calls)
+	}
+
+	// Following keeps polling with the unchanged cursor until cancelled
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	calls = nil
+	it = NewTradesIterator(api.Public(), XXBTZEUR, 300)
+	it.Follow = true
+	it.PollInterval = 10 * time.Millisecond
+	for it.Next(ctx) {
+		t.Errorf("TradesIterator should not return trades without new data")
+	}
+	if it.Err() != context.DeadlineExceeded || len(calls) > 10 {
+		t.Errorf("TradesIterator should wait between polls until cancelled, got %v after %d calls", it.Err(), len(calls))
+	}
+}
@@ -4,11 +4,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"sort"
 	"strconv"
 	"time"
 )
 
 // trade pairs constants
+//
+// Deprecated: the list is incomplete and holds delisted pairs, use a PairResolver built
+// from AssetPairs to get the canonical name of any pair.
 const (
 	ADACAD   = "ADACAD"
 	ADAETH   = "ADAETH"
@@ -107,6 +111,43 @@ type TimeResponse struct {
 	Rfc1123 string
 }
 
+// SystemStatus is the system status or trading mode of Kraken
+type SystemStatus string
+
+// SystemStatus values
+const (
+	// Kraken is operating normally
+	StatusOnline SystemStatus = "online"
+	// Kraken is offline, no new orders or cancellations
+	StatusMaintenance SystemStatus = "maintenance"
+	// Resting orders can be cancelled, no new orders
+	StatusCancelOnly SystemStatus = "cancel_only"
+	// Only new post-only limit orders can be submitted
+	StatusPostOnly SystemStatus = "post_only"
+)
+
+// SystemStatusResponse represents the system status
+type SystemStatusResponse struct {
+	Status SystemStatus `json:"status"`
+	// RFC 3339 time of the status
+	Timestamp string `json:"timestamp"`
+}
+
+// PairInfoMode selects the asset pair information returned by AssetPairsWithInfo
+type PairInfoMode string
+
+// PairInfoMode values
+const (
+	// All info
+	PairInfoAll PairInfoMode = "info"
+	// Leverage info
+	PairInfoLeverage PairInfoMode = "leverage"
+	// Fees schedule
+	PairInfoFees PairInfoMode = "fees"
+	// Margin info
+	PairInfoMargin PairInfoMode = "margin"
+)
+
 // AssetPairsResponse includes asset pair informations
 type AssetPairsResponse interface {
 	// GetAsset is a helper method that returns given `pair`'s `AssetInfo`
@@ -132,6 +173,8 @@ func (ap *AssetPairs) GetPairs() []string {
 type AssetPairInfo struct {
 	// Alternate pair name
 	Altname string `json:"altname"`
+	// WebSocket pair name (if available)
+	WSName string `json:"wsname"`
 	// Asset class of base component
 	AssetClassBase string `json:"aclass_base"`
 	// Asset id of base component
@@ -146,6 +189,8 @@ type AssetPairInfo struct {
 	PairDecimals int `json:"pair_decimals"`
 	// Scaling decimal places for volume
 	LotDecimals int `json:"lot_decimals"`
+	// Scaling decimal places for cost
+	CostDecimals int `json:"cost_decimals"`
 	// Amount to multiply lot volume by to get currency volume
 	LotMultiplier int `json:"lot_multiplier"`
 	// Array of leverage amounts available when buying
@@ -159,9 +204,21 @@ type AssetPairInfo struct {
 	// // Volume discount currency
 	FeeVolumeCurrency string `json:"fee_volume_currency"`
 	// Margin call level
-	MarginCall int `json:"margin_call"`
+	MarginCall float64 `json:"margin_call"`
 	// Stop-out/Liquidation margin level
-	MarginStop int `json:"margin_stop"`
+	MarginStop float64 `json:"margin_stop"`
+	// Minimum order volume for pair
+	OrderMin float64 `json:"ordermin,string"`
+	// Minimum order cost (in quote currency)
+	CostMin float64 `json:"costmin,string"`
+	// Minimum price increment (in quote currency)
+	TickSize float64 `json:"tick_size,string"`
+	// Status of asset pair: online, cancel_only, post_only, limit_only or reduce_only
+	Status string `json:"status"`
+	// Maximum long margin position size (in base currency)
+	LongPositionLimit int `json:"long_position_limit"`
+	// Maximum short margin position size (in base currency)
+	ShortPositionLimit int `json:"short_position_limit"`
 }
 
 // AssetsResponse includes asset informations
@@ -169,8 +226,22 @@ type AssetsResponse interface {
 	// GetAsset is a helper method that returns given `pair`'s `AssetInfo`
 	GetAsset(asset string) AssetInfo
 	GetAssets() []string
+	// GetAssetByAltname is a helper method that returns the asset with given alternate name
+	GetAssetByAltname(altname string) (string, AssetInfo, bool)
+	// GetEnabledAssets is a helper method that returns the assets that can be deposited and withdrawn
+	GetEnabledAssets() []string
+	// GetDepositAssets is a helper method that returns the assets that can be deposited
+	GetDepositAssets() []string
 }
 
+// Asset status values
+const (
+	AssetStatusEnabled         = "enabled"
+	AssetStatusDepositOnly     = "deposit_only"
+	AssetStatusWithdrawalOnly  = "withdrawal_only"
+	AssetStatusFundingDisabled = "funding_temporarily_disabled"
+)
+
 type Assets map[string]AssetInfo
 
 func (a *Assets) GetAsset(asset string) AssetInfo {
@@ -185,6 +256,38 @@ func (a *Assets) GetAssets() []string {
 	return assets
 }
 
+func (a *Assets) GetAssetByAltname(altname string) (string, AssetInfo, bool) {
+	for asset, info := range *a {
+		if info.Altname == altname {
+			return asset, info, true
+		}
+	}
+	return "", AssetInfo{}, false
+}
+
+func (a *Assets) GetEnabledAssets() []string {
+	return a.filter(func(info AssetInfo) bool {
+		return info.Status == AssetStatusEnabled
+	})
+}
+
+func (a *Assets) GetDepositAssets() []string {
+	return a.filter(func(info AssetInfo) bool {
+		return info.Status == AssetStatusEnabled || info.Status == AssetStatusDepositOnly
+	})
+}
+
+func (a *Assets) filter(keep func(info AssetInfo) bool) []string {
+	var assets []string
+	for asset, info := range *a {
+		if keep(info) {
+			assets = append(assets, asset)
+		}
+	}
+	sort.Strings(assets)
+	return assets
+}
+
 // AssetInfo represents an asset information
 type AssetInfo struct {
 	// Alternate name
@@ -195,6 +298,12 @@ type AssetInfo struct {
 	Decimals int
 	// Scaling decimal places for output display
 	DisplayDecimals int `json:"display_decimals"`
+	// Valuation as margin collateral (if applicable)
+	CollateralValue float64 `json:"collateral_value"`
+	// Status of asset: enabled, deposit_only, withdrawal_only or funding_temporarily_disabled
+	Status string `json:"status"`
+	// Interest rate for margin positions (if applicable)
+	MarginRate float64 `json:"margin_rate"`
 }
 
 type BalanceResponse interface {
@@ -267,6 +376,28 @@ type TradeVolumeResponse struct {
 	FeesMaker Fees    `json:"fees_maker"`
 }
 
+// UnmarshalJSON decodes the fees of each pair into a FeesMap, Fees and FeesMaker stay nil if the response has none
+func (t *TradeVolumeResponse) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Volume    float64  `json:"volume,string"`
+		Currency  string   `json:"currency"`
+		Fees      *FeesMap `json:"fees"`
+		FeesMaker *FeesMap `json:"fees_maker"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*t = TradeVolumeResponse{Volume: raw.Volume, Currency: raw.Currency}
+	if raw.Fees != nil {
+		t.Fees = raw.Fees
+	}
+	if raw.FeesMaker != nil {
+		t.FeesMaker = raw.FeesMaker
+	}
+	return nil
+}
+
 // TickerResponse includes the requested ticker pairs
 type TickerResponse interface {
 	// GetPairTickerInfo is a helper method that returns given `pair`'s `PairTickerInfo`
@@ -328,6 +459,32 @@ type PairTickerInfo struct {
 	High []string `json:"h"`
 	// Today's opening price
 	OpeningPrice float64 `json:"o,string"`
+
+	// openingPrice is OpeningPrice exactly as Kraken sent it
+	openingPrice string
+}
+
+// UnmarshalJSON decodes the ticker and keeps the opening price exactly as sent, see Parsed
+func (t *PairTickerInfo) UnmarshalJSON(data []byte) error {
+	type plain PairTickerInfo
+	var raw struct {
+		plain
+		// Shadows plain's OpeningPrice, which is parsed from it
+		OpeningPrice string `json:"o"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*t = PairTickerInfo(raw.plain)
+	if raw.OpeningPrice != "" {
+		price, err := strconv.ParseFloat(raw.OpeningPrice, 64)
+		if err != nil {
+			return fmt.Errorf("invalid ticker opening price %q", raw.OpeningPrice)
+		}
+		t.OpeningPrice, t.openingPrice = price, raw.OpeningPrice
+	}
+	return nil
 }
 
 // TradesResponse represents a list of the last trades
@@ -336,6 +493,20 @@ type TradesResponse struct {
 	Trades []TradeInfo
 }
 
+// SpreadResponse represents a list of the last spreads
+type SpreadResponse struct {
+	Pair    string
+	Spreads []SpreadItem
+	Last    int64
+}
+
+// SpreadItem represents the best bid and ask at a point in time
+type SpreadItem struct {
+	Time time.Time
+	Bid  Decimal
+	Ask  Decimal
+}
+
 // TradesHistoryResponse represents a list of executed trade
 type TradesHistoryResponse struct {
 	Trades map[string]TradeHistoryInfo `json:"trades"`
@@ -0,0 +1,332 @@
+package krakenapi
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+)
+
+// Withdrawal policy violations, wrapped by WithdrawalError
+var (
+	ErrWithdrawInvalidAmount = errors.New("withdrawal amount not positive")
+	ErrWithdrawKeyNotAllowed = errors.New("withdrawal key not allowlisted")
+	ErrWithdrawLimitExceeded = errors.New("withdrawal limit exceeded")
+	ErrWithdrawFeeTooHigh    = errors.New("withdrawal fee too high")
+	ErrWithdrawNotApproved   = errors.New("withdrawal not approved")
+)
+
+// WithdrawalError describes why the withdrawal policy refused a withdrawal
+type WithdrawalError struct {
+	Asset  string
+	Key    string
+	Reason string
+	Err    error
+}
+
+func (e *WithdrawalError) Error() string {
+	return fmt.Sprintf("withdrawal of %s to %q refused: %s (%s)", e.Asset, e.Key, e.Err, e.Reason)
+}
+
+func (e *WithdrawalError) Unwrap() error {
+	return e.Err
+}
+
+// WithdrawalRequest is a withdrawal waiting for approval
+type WithdrawalRequest struct {
+	Asset  string
+	Key    string
+	Amount *big.Float
+	Info   *WithdrawInfoResponse
+}
+
+// WithdrawalPolicy configures the checks done before a withdrawal is sent
+type WithdrawalPolicy struct {
+	// Withdrawal key names allowed per asset, assets without entry can't be withdrawn
+	AllowedKeys map[string][]string
+	// Maximum amount of a single withdrawal per asset
+	MaxPerTransaction map[string]*big.Float
+	// Maximum total amount per asset withdrawn within Window
+	MaxPerWindow map[string]*big.Float
+	// Rolling window for MaxPerWindow, 24 hours if zero
+	Window time.Duration
+	// Maximum fee / amount ratio returned by WithdrawInfo, e.g. 0.01 for 1%
+	MaxFeeRatio float64
+	// Optional human sign-off, the withdrawal is refused unless it returns true
+	Approve func(req WithdrawalRequest) bool
+	// Audit log file every attempt is appended to
+	AuditLog string
+}
+
+// withdrawalRecord is a withdrawal sent or reserved within the rolling window
+type withdrawalRecord struct {
+	asset  string
+	amount *big.Float
+	time   time.Time
+}
+
+// WithdrawalGuard wraps a PrivateAPI and applies a WithdrawalPolicy to every withdrawal,
+// whether it is made with Withdraw or with Query.
+type WithdrawalGuard struct {
+	PrivateAPI
+	policy WithdrawalPolicy
+	audit  *os.File
+	now    func() time.Time
+
+	mu      sync.Mutex
+	history []*withdrawalRecord
+}
+
+// NewWithdrawalGuard creates a WithdrawalGuard and opens the policy's audit log for appending.
+// The withdrawals sent within the window are read back from the audit log, so the window limit
+// survives restarts.
+func NewWithdrawalGuard(api PrivateAPI, policy WithdrawalPolicy) (*WithdrawalGuard, error) {
+	if policy.AuditLog == "" {
+		return nil, errors.New("withdrawal policy needs an audit log")
+	}
+	if policy.Window <= 0 {
+		policy.Window = 24 * time.Hour
+	}
+
+	g := &WithdrawalGuard{
+		PrivateAPI: api,
+		policy:     policy,
+		now:        time.Now,
+	}
+	if err := g.loadHistory(); err != nil {
+		return nil, err
+	}
+
+	audit, err := os.OpenFile(policy.AuditLog, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	g.audit = audit
+
+	return g, nil
+}
+
+// loadHistory rebuilds the withdrawals sent within the window from the audit log
+func (g *WithdrawalGuard) loadHistory() error {
+	file, err := os.Open(g.policy.AuditLog)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	cutoff := g.now().Add(-g.policy.Window)
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		var entry withdrawalAuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return fmt.Errorf("withdrawal audit log line %d: %s", line, err)
+		}
+		if entry.Result != "sent" || !entry.Time.After(cutoff) {
+			continue
+		}
+		amount, ok := new(big.Float).SetString(entry.Amount)
+		if !ok {
+			return fmt.Errorf("withdrawal audit log line %d: invalid amount %q", line, entry.Amount)
+		}
+		g.history = append(g.history, &withdrawalRecord{asset: entry.Asset, amount: amount, time: entry.Time})
+	}
+	return scanner.Err()
+}
+
+// Close closes the audit log
+func (g *WithdrawalGuard) Close() error {
+	return g.audit.Close()
+}
+
+// Withdraw checks the withdrawal against the policy and only executes it if every check passes
+func (g *WithdrawalGuard) Withdraw(asset string, key string, amount *big.Float) (*WithdrawResponse, error) {
+	entry := withdrawalAuditEntry{
+		Time:  g.now().UTC(),
+		Asset: asset,
+		Key:   key,
+	}
+	if amount != nil {
+		entry.Amount = amount.Text('f', -1)
+	}
+
+	resp, err := g.withdraw(asset, key, amount, &entry)
+	if err != nil {
+		entry.Result = "refused"
+		if _, ok := err.(*WithdrawalError); !ok {
+			entry.Result = "failed"
+		}
+		entry.Error = err.Error()
+	} else {
+		entry.Result = "sent"
+		entry.RefID = resp.RefID
+	}
+
+	if auditErr := g.writeAudit(entry); auditErr != nil && err == nil {
+		return resp, fmt.Errorf("withdrawal %s sent but audit log failed: %s", resp.RefID, auditErr)
+	}
+
+	return resp, err
+}
+
+// Query executes any private method by name, withdrawals go through the policy like Withdraw
+func (g *WithdrawalGuard) Query(method string, args map[string]string) (interface{}, error) {
+	if method != "Withdraw" {
+		return g.PrivateAPI.Query(method, args)
+	}
+	amount, ok := new(big.Float).SetString(args["amount"])
+	if !ok {
+		amount = nil
+	}
+	return g.Withdraw(args["asset"], args["key"], amount)
+}
+
+func (g *WithdrawalGuard) withdraw(asset string, key string, amount *big.Float, entry *withdrawalAuditEntry) (*WithdrawResponse, error) {
+	if amount == nil || amount.Sign() <= 0 {
+		return nil, g.violation(asset, key, ErrWithdrawInvalidAmount, "amount must be positive")
+	}
+	if !g.keyAllowed(asset, key) {
+		return nil, g.violation(asset, key, ErrWithdrawKeyNotAllowed, "key is not in the allowlist")
+	}
+
+	if limit, ok := g.policy.MaxPerTransaction[asset]; ok && amount.Cmp(limit) > 0 {
+		return nil, g.violation(asset, key, ErrWithdrawLimitExceeded,
+			fmt.Sprintf("amount %s is above per transaction limit %s", amount.Text('f', -1), limit.Text('f', -1)))
+	}
+
+	// Reserve the amount so concurrent withdrawals can't overrun the window limit while
+	// this one waits for WithdrawInfo and approval, and release it unless it was sent
+	record, err := g.reserve(asset, key, amount)
+	if err != nil {
+		return nil, err
+	}
+	sent := false
+	defer func() {
+		if !sent {
+			g.release(record)
+		}
+	}()
+
+	info, err := g.PrivateAPI.WithdrawInfo(asset, key, amount)
+	if err != nil {
+		return nil, err
+	}
+	entry.Fee = info.Fee.Text('f', -1)
+
+	ratio, _ := new(big.Float).Quo(&info.Fee, amount).Float64()
+	if ratio > g.policy.MaxFeeRatio {
+		return nil, g.violation(asset, key, ErrWithdrawFeeTooHigh,
+			fmt.Sprintf("fee %s is %.4f%% of the amount, maximum is %.4f%%", entry.Fee, ratio*100, g.policy.MaxFeeRatio*100))
+	}
+
+	if g.policy.Approve != nil {
+		req := WithdrawalRequest{Asset: asset, Key: key, Amount: amount, Info: info}
+		if !g.policy.Approve(req) {
+			return nil, g.violation(asset, key, ErrWithdrawNotApproved, "approval callback declined")
+		}
+	}
+
+	resp, err := g.PrivateAPI.Withdraw(asset, key, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	sent = true
+	g.mu.Lock()
+	record.time = g.now()
+	g.mu.Unlock()
+	return resp, nil
+}
+
+// reserve adds amount to the window if it stays within the window limit
+func (g *WithdrawalGuard) reserve(asset, key string, amount *big.Float) (*withdrawalRecord, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if limit, ok := g.policy.MaxPerWindow[asset]; ok {
+		total := new(big.Float).Add(g.withdrawn(asset), amount)
+		if total.Cmp(limit) > 0 {
+			return nil, g.violation(asset, key, ErrWithdrawLimitExceeded,
+				fmt.Sprintf("%s within %s would be above limit %s", total.Text('f', -1), g.policy.Window, limit.Text('f', -1)))
+		}
+	}
+
+	record := &withdrawalRecord{asset: asset, amount: new(big.Float).Set(amount), time: g.now()}
+	g.history = append(g.history, record)
+	return record, nil
+}
+
+// release removes a reservation that was not sent
+func (g *WithdrawalGuard) release(record *withdrawalRecord) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for i, r := range g.history {
+		if r == record {
+			g.history = append(g.history[:i], g.history[i+1:]...)
+			return
+		}
+	}
+}
+
+func (g *WithdrawalGuard) violation(asset, key string, err error, reason string) error {
+	return &WithdrawalError{Asset: asset, Key: key, Reason: reason, Err: err}
+}
+
+// keyAllowed returns true if key is allowlisted for asset
+func (g *WithdrawalGuard) keyAllowed(asset, key string) bool {
+	for _, allowed := range g.policy.AllowedKeys[asset] {
+		if allowed == key {
+			return true
+		}
+	}
+	return false
+}
+
+// withdrawn returns the amount of asset withdrawn or reserved within the rolling window and forgets
+// older withdrawals, g.mu must be held
+func (g *WithdrawalGuard) withdrawn(asset string) *big.Float {
+	cutoff := g.now().Add(-g.policy.Window)
+	total := new(big.Float)
+	history := g.history[:0]
+	for _, record := range g.history {
+		if !record.time.After(cutoff) {
+			continue
+		}
+		history = append(history, record)
+		if record.asset == asset {
+			total.Add(total, record.amount)
+		}
+	}
+	g.history = history
+	return total
+}
+
+// withdrawalAuditEntry is a line of the withdrawal audit log
+type withdrawalAuditEntry struct {
+	Time   time.Time `json:"time"`
+	Asset  string    `json:"asset"`
+	Key    string    `json:"key"`
+	Amount string    `json:"amount"`
+	Fee    string    `json:"fee,omitempty"`
+	Result string    `json:"result"`
+	RefID  string    `json:"refid,omitempty"`
+	Error  string    `json:"error,omitempty"`
+}
+
+func (g *WithdrawalGuard) writeAudit(entry withdrawalAuditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	_, err = g.audit.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,151 @@
+package krakenapi
+
+import (
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithdrawalGuard(t *testing.T) {
+	var withdrawn []string
+	api := newTestAPI(func(method string, values url.Values) string {
+		switch method {
+		case "WithdrawInfo":
+			return `{"error":[],"result":{"method":"Bitcoin","limit":"10","amount":"` + values.Get("amount") + `","fee":"0.001"}}`
+		case "Withdraw":
+			withdrawn = append(withdrawn, values.Get("amount"))
+			return `{"error":[],"result":{"refid":"AGBSO6T-UFMTTQ-I7KGS6"}}`
+		}
+		return `{"error":["EGeneral:Invalid arguments"]}`
+	})
+
+	auditLog := filepath.Join(t.TempDir(), "withdrawals.log")
+	approved := true
+	guard, err := NewWithdrawalGuard(api.Private(), WithdrawalPolicy{
+		AllowedKeys:       map[string][]string{"XXBT": {"cold wallet"}},
+		MaxPerTransaction: map[string]*big.Float{"XXBT": big.NewFloat(2)},
+		MaxPerWindow:      map[string]*big.Float{"XXBT": big.NewFloat(3)},
+		MaxFeeRatio:       0.01,
+		Approve:           func(req WithdrawalRequest) bool { return approved },
+		AuditLog:          auditLog,
+	})
+	if err != nil {
+		t.Fatalf("NewWithdrawalGuard() should not return an error, got %s", err)
+	}
+	defer guard.Close()
+
+	tests := []struct {
+		key      string
+		amount   float64
+		approved bool
+		err      error
+	}{
+		{"hot wallet", 1, true, ErrWithdrawKeyNotAllowed},
+		{"cold wallet", 2.5, true, ErrWithdrawLimitExceeded},
+		{"cold wallet", 0.05, true, ErrWithdrawFeeTooHigh},
+		{"cold wallet", 1, false, ErrWithdrawNotApproved},
+		{"cold wallet", 2, true, nil},
+		{"cold wallet", 1.5, true, ErrWithdrawLimitExceeded},
+		{"cold wallet", 1, true, nil},
+	}
+	for _, test := range tests {
+		approved = test.approved
+		_, err := guard.Withdraw("XXBT", test.key, big.NewFloat(test.amount))
+		if !errors.Is(err, test.err) {
+			t.Errorf("Withdraw(%s, %v) should return %v, got %v", test.key, test.amount, test.err, err)
+		}
+	}
+
+	if strings.Join(withdrawn, ",") != "2,1" {
+		t.Errorf("Only allowed withdrawals should be sent, got %v", withdrawn)
+	}
+
+	audit, _ := ioutil.ReadFile(auditLog)
+	if lines := strings.Count(string(audit), "\n"); lines != len(tests) {
+		t.Errorf("Every attempt should be audited, got %d lines", lines)
+	}
+
+	args := map[string]string{"asset": "XXBT", "key": "cold wallet", "amount": "0.5"}
+	if _, err := guard.Query("Withdraw", args); !errors.Is(err, ErrWithdrawLimitExceeded) {
+		t.Errorf("Query(Withdraw) should apply the policy, got %v", err)
+	}
+	if _, err := guard.Withdraw("XXBT", "cold wallet", nil); !errors.Is(err, ErrWithdrawInvalidAmount) {
+		t.Errorf("Withdraw(nil) should return ErrWithdrawInvalidAmount, got %v", err)
+	}
+
+	// A restarted guard reads the window back from the audit log
+	restarted, err := NewWithdrawalGuard(api.Private(), guard.policy)
+	if err != nil {
+		t.Fatalf("NewWithdrawalGuard() should read the audit log, got %s", err)
+	}
+	defer restarted.Close()
+	if _, err := restarted.Withdraw("XXBT", "cold wallet", big.NewFloat(0.5)); !errors.Is(err, ErrWithdrawLimitExceeded) {
+		t.Errorf("Withdraw() after a restart should count earlier withdrawals, got %v", err)
+	}
+}
+
+func TestWithdrawalGuardApproval(t *testing.T) {
+	api := newTestAPI(func(method string, values url.Values) string {
+		switch method {
+		case "WithdrawInfo":
+			return `{"error":[],"result":{"method":"Bitcoin","limit":"10","amount":"` + values.Get("amount") + `","fee":"0"}}`
+		case "Withdraw":
+			return `{"error":[],"result":{"refid":"AGBSO6T-UFMTTQ-I7KGS6"}}`
+		}
+		return `{"error":["EGeneral:Invalid arguments"]}`
+	})
+
+	signOff := make(chan bool)
+	guard, err := NewWithdrawalGuard(api.Private(), WithdrawalPolicy{
+		AllowedKeys:  map[string][]string{"XXBT": {"cold wallet"}},
+		MaxPerWindow: map[string]*big.Float{"XXBT": big.NewFloat(3)},
+		Approve: func(req WithdrawalRequest) bool {
+			if req.Amount.Cmp(big.NewFloat(2)) == 0 {
+				return <-signOff
+			}
+			return true
+		},
+		AuditLog: filepath.Join(t.TempDir(), "withdrawals.log"),
+	})
+	if err != nil {
+		t.Fatalf("NewWithdrawalGuard() should not return an error, got %s", err)
+	}
+	defer guard.Close()
+
+	waiting := make(chan error)
+	go func() {
+		_, err := guard.Withdraw("XXBT", "cold wallet", big.NewFloat(2))
+		waiting <- err
+	}()
+
+	// Wait for the first withdrawal to reserve its amount
+	for {
+		guard.mu.Lock()
+		reserved := len(guard.history)
+		guard.mu.Unlock()
+		if reserved == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if _, err := guard.Withdraw("XXBT", "cold wallet", big.NewFloat(1.5)); !errors.Is(err, ErrWithdrawLimitExceeded) {
+		t.Errorf("Withdraw() should count the amount waiting for approval, got %v", err)
+	}
+	if _, err := guard.Withdraw("XXBT", "cold wallet", big.NewFloat(1)); err != nil {
+		t.Errorf("Withdraw() should not wait for another approval, got %v", err)
+	}
+
+	signOff <- false
+	if err := <-waiting; !errors.Is(err, ErrWithdrawNotApproved) {
+		t.Errorf("Withdraw() should be refused when declined, got %v", err)
+	}
+	if _, err := guard.Withdraw("XXBT", "cold wallet", big.NewFloat(1.5)); err != nil {
+		t.Errorf("Withdraw() should be allowed once the declined amount is released, got %v", err)
+	}
+}